@@ -4,7 +4,13 @@ Copyright © 2026 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/sblackstone/go-kitty/kitty"
 	"github.com/spf13/cobra"
@@ -13,18 +19,74 @@ import (
 var (
 	snakeCount          int
 	snakeMaxLen         int
+	snakeStartLen       int
 	snakeInitialDelayMax int
+	snakeSeek           float64
+	snakeFlee           float64
+	snakeSeparation     float64
+	snakeWebAvoid       float64
+	snakeEatChance      float64
 	stringCount         int
 	stringMinLen        int
 	stringMaxLen        int
 	stringInitialDelayMax int
 	butterflyCount      int
 	butterflyInitialDelayMax int
+	mouseCount          int
+	mouseInitialDelayMax int
+	mouseWebChance      float64
+	fishCount           int
+	fishSize            int
+	fishSpeed           float64
+	fishInitialDelayMax int
+	wandCount           int
+	wandLength          int
+	wandStiffness       float64
+	wandDamping         float64
+	wandInitialDelayMax int
+	yarnCount           int
+	yarnLength          int
+	yarnFriction        float64
+	yarnInitialDelayMax int
+	flyCount            int
+	flyInitialDelayMax  int
+	fireflyCount        int
+	fireflyInitialDelayMax int
+	birdCount           int
+	birdInitialDelayMax int
+	birdDiveChance      float64
 	laserCount          int
 	laserInitialDelayMax int
+	laserCatchAfter     time.Duration
+	laserSpeed          float64
 	spiderCount         int
 	spiderInitialDelayMax int
+	spiderWebShape      string
+	spiderWebRadius     int
+	spiderWebSpokes     int
+	spiderWebRings      int
 	laserHitsSpiders    bool
+	sessionDuration     time.Duration
+	sessionPlay         time.Duration
+	sessionRest         time.Duration
+	sessionSchedule     string
+	intensity           float64
+	intensityCurve      string
+	statsFile           string
+	metricsAddr         string
+	logFile             string
+	debug               bool
+	seed                int64
+	layers              string
+	maxParticles        int
+	spritesDir          string
+	scripts             []string
+	extensions          []string
+	backgroundColor     string
+	groundColor         string
+	groundRows          int
+	palette             string
+	sceneName           string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -33,30 +95,161 @@ var rootCmd = &cobra.Command{
 	Short: "Cat Entertainment",
 	Long:  `A way to entertain a cat looking at a terminal window`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg := kitty.DefaultKittyConfig()
-		cfg.SnakeCount = snakeCount
-		cfg.SnakeConfig.MaxLen = snakeMaxLen
-		cfg.SnakeConfig.InitialDelayMax = snakeInitialDelayMax
-		cfg.SwayStringCount = stringCount
-		cfg.SwayStringConfig.MinLen = stringMinLen
-		cfg.SwayStringConfig.MaxLen = stringMaxLen
-		cfg.SwayStringConfig.InitialDelayMax = stringInitialDelayMax
-		cfg.ButterflyCount = butterflyCount
-		cfg.ButterflyConfig.InitialDelayMax = butterflyInitialDelayMax
-		cfg.LaserCount = laserCount
-		cfg.LaserConfig.InitialDelayMax = laserInitialDelayMax
-		cfg.SpiderCount = spiderCount
-		cfg.SpiderConfig.InitialDelayMax = spiderInitialDelayMax
-		cfg.LaserHitsSpiders = laserHitsSpiders
-
-		k, err := kitty.New(cfg)
+		cfg, err := buildConfig(cmd)
 		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := play(cmd.Context(), cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
-		k.Start(cmd.Context())
 	},
 }
 
+// flagFields copies each flag that only sets a config field into the config.
+var flagFields = map[string]func(cfg *kitty.KittyConfig){
+	"snakes":                      func(cfg *kitty.KittyConfig) { cfg.SnakeCount = snakeCount },
+	"snake-max-len":               func(cfg *kitty.KittyConfig) { cfg.SnakeConfig.MaxLen = snakeMaxLen },
+	"snake-start-len":             func(cfg *kitty.KittyConfig) { cfg.SnakeConfig.StartLen = snakeStartLen },
+	"snake-initial-delay-max":     func(cfg *kitty.KittyConfig) { cfg.SnakeConfig.InitialDelayMax = snakeInitialDelayMax },
+	"snake-seek":                  func(cfg *kitty.KittyConfig) { cfg.SnakeConfig.SeekWeight = snakeSeek },
+	"snake-flee":                  func(cfg *kitty.KittyConfig) { cfg.SnakeConfig.FleeWeight = snakeFlee },
+	"snake-separation":            func(cfg *kitty.KittyConfig) { cfg.SnakeConfig.SeparationWeight = snakeSeparation },
+	"snake-web-avoid":             func(cfg *kitty.KittyConfig) { cfg.SnakeConfig.WebWeight = snakeWebAvoid },
+	"snake-eat-chance":            func(cfg *kitty.KittyConfig) { cfg.SnakeConfig.EatChance = snakeEatChance },
+	"strings":                     func(cfg *kitty.KittyConfig) { cfg.SwayStringCount = stringCount },
+	"string-min-len":              func(cfg *kitty.KittyConfig) { cfg.SwayStringConfig.MinLen = stringMinLen },
+	"string-max-len":              func(cfg *kitty.KittyConfig) { cfg.SwayStringConfig.MaxLen = stringMaxLen },
+	"string-initial-delay-max":    func(cfg *kitty.KittyConfig) { cfg.SwayStringConfig.InitialDelayMax = stringInitialDelayMax },
+	"butterflies":                 func(cfg *kitty.KittyConfig) { cfg.ButterflyCount = butterflyCount },
+	"butterfly-initial-delay-max": func(cfg *kitty.KittyConfig) { cfg.ButterflyConfig.InitialDelayMax = butterflyInitialDelayMax },
+	"mice":                        func(cfg *kitty.KittyConfig) { cfg.MouseCount = mouseCount },
+	"mouse-initial-delay-max":     func(cfg *kitty.KittyConfig) { cfg.MouseConfig.InitialDelayMax = mouseInitialDelayMax },
+	"mouse-web-chance":            func(cfg *kitty.KittyConfig) { cfg.MouseConfig.WebChance = mouseWebChance },
+	"fish":                        func(cfg *kitty.KittyConfig) { cfg.FishCount = fishCount },
+	"fish-size":                   func(cfg *kitty.KittyConfig) { cfg.FishConfig.Size = fishSize },
+	"fish-speed":                  func(cfg *kitty.KittyConfig) { cfg.FishConfig.Speed = fishSpeed },
+	"fish-initial-delay-max":      func(cfg *kitty.KittyConfig) { cfg.FishConfig.InitialDelayMax = fishInitialDelayMax },
+	"wands":                       func(cfg *kitty.KittyConfig) { cfg.WandCount = wandCount },
+	"wand-length":                 func(cfg *kitty.KittyConfig) { cfg.WandConfig.Length = wandLength },
+	"wand-stiffness":              func(cfg *kitty.KittyConfig) { cfg.WandConfig.Stiffness = wandStiffness },
+	"wand-damping":                func(cfg *kitty.KittyConfig) { cfg.WandConfig.Damping = wandDamping },
+	"wand-initial-delay-max":      func(cfg *kitty.KittyConfig) { cfg.WandConfig.InitialDelayMax = wandInitialDelayMax },
+	"yarn":                        func(cfg *kitty.KittyConfig) { cfg.YarnCount = yarnCount },
+	"yarn-length":                 func(cfg *kitty.KittyConfig) { cfg.YarnConfig.Length = yarnLength },
+	"yarn-friction":               func(cfg *kitty.KittyConfig) { cfg.YarnConfig.Friction = yarnFriction },
+	"yarn-initial-delay-max":      func(cfg *kitty.KittyConfig) { cfg.YarnConfig.InitialDelayMax = yarnInitialDelayMax },
+	"flies":                       func(cfg *kitty.KittyConfig) { cfg.FlyCount = flyCount },
+	"fly-initial-delay-max":       func(cfg *kitty.KittyConfig) { cfg.FlyConfig.InitialDelayMax = flyInitialDelayMax },
+	"fireflies":                   func(cfg *kitty.KittyConfig) { cfg.FireflyCount = fireflyCount },
+	"firefly-initial-delay-max":   func(cfg *kitty.KittyConfig) { cfg.FireflyConfig.InitialDelayMax = fireflyInitialDelayMax },
+	"birds":                       func(cfg *kitty.KittyConfig) { cfg.BirdCount = birdCount },
+	"bird-initial-delay-max":      func(cfg *kitty.KittyConfig) { cfg.BirdConfig.InitialDelayMax = birdInitialDelayMax },
+	"bird-dive-chance":            func(cfg *kitty.KittyConfig) { cfg.BirdConfig.DiveChance = birdDiveChance },
+	"lasers":                      func(cfg *kitty.KittyConfig) { cfg.LaserCount = laserCount },
+	"laser-initial-delay-max":     func(cfg *kitty.KittyConfig) { cfg.LaserConfig.InitialDelayMax = laserInitialDelayMax },
+	"laser-catch-after":           func(cfg *kitty.KittyConfig) { cfg.LaserConfig.CatchAfter = laserCatchAfter },
+	"laser-speed":                 func(cfg *kitty.KittyConfig) { cfg.LaserConfig.Speed = laserSpeed },
+	"spiders":                     func(cfg *kitty.KittyConfig) { cfg.SpiderCount = spiderCount },
+	"spider-initial-delay-max":    func(cfg *kitty.KittyConfig) { cfg.SpiderConfig.InitialDelayMax = spiderInitialDelayMax },
+	"spider-web":                  func(cfg *kitty.KittyConfig) { cfg.SpiderConfig.WebShape = spiderWebShape },
+	"spider-web-radius":           func(cfg *kitty.KittyConfig) { cfg.SpiderConfig.WebRadius = spiderWebRadius },
+	"spider-web-spokes":           func(cfg *kitty.KittyConfig) { cfg.SpiderConfig.WebSpokes = spiderWebSpokes },
+	"spider-web-rings":            func(cfg *kitty.KittyConfig) { cfg.SpiderConfig.WebRings = spiderWebRings },
+	"laser-hits-spiders":          func(cfg *kitty.KittyConfig) { cfg.LaserHitsSpiders = laserHitsSpiders },
+	"ground-rows":                 func(cfg *kitty.KittyConfig) { cfg.Background.GroundRows = groundRows },
+	"palette":                     func(cfg *kitty.KittyConfig) { cfg.Background.Palette = palette },
+	"duration":                    func(cfg *kitty.KittyConfig) { cfg.Session.Duration = sessionDuration },
+	"play":                        func(cfg *kitty.KittyConfig) { cfg.Session.Play = sessionPlay },
+	"rest":                        func(cfg *kitty.KittyConfig) { cfg.Session.Rest = sessionRest },
+	"schedule":                    func(cfg *kitty.KittyConfig) { cfg.Session.Schedule = sessionSchedule },
+	"intensity":                   func(cfg *kitty.KittyConfig) { cfg.Intensity = intensity },
+	"metrics-addr":                func(cfg *kitty.KittyConfig) { cfg.MetricsAddr = metricsAddr },
+	"debug":                       func(cfg *kitty.KittyConfig) { cfg.Debug = debug },
+	"seed":                        func(cfg *kitty.KittyConfig) { cfg.Seed = seed },
+	"max-particles":               func(cfg *kitty.KittyConfig) { cfg.MaxParticles = maxParticles },
+	"sprites":                     func(cfg *kitty.KittyConfig) { cfg.SpritesDir = expandHome(spritesDir) },
+}
+
+// buildConfig starts from the --scene config, or the defaults without one,
+// and applies only the flags given on the command line on top. Flags so win
+// over the scene wherever they come on the command line.
+func buildConfig(cmd *cobra.Command) (kitty.KittyConfig, error) {
+	cfg := kitty.DefaultKittyConfig()
+	if sceneName != "" {
+		sc, err := kitty.SceneByName(sceneName)
+		if err != nil {
+			return cfg, err
+		}
+		cfg = sc.Config()
+	}
+	flags := cmd.Flags()
+	for name, apply := range flagFields {
+		if flags.Changed(name) {
+			apply(&cfg)
+		}
+	}
+	var err error
+	if flags.Changed("background") {
+		if cfg.Background.Color, err = kitty.ParseColor(backgroundColor); err != nil {
+			return cfg, err
+		}
+	}
+	if flags.Changed("ground") {
+		if cfg.Background.GroundColor, err = kitty.ParseColor(groundColor); err != nil {
+			return cfg, err
+		}
+	}
+	if flags.Changed("intensity-curve") {
+		if cfg.IntensityCurve, err = kitty.ParseIntensityCurve(intensityCurve); err != nil {
+			return cfg, err
+		}
+	}
+	if flags.Changed("layers") {
+		if cfg.Layers, err = kitty.ParseLayers(layers); err != nil {
+			return cfg, err
+		}
+	}
+	for _, path := range scripts {
+		cfg.Scripts = append(cfg.Scripts, expandHome(path))
+	}
+	for _, spec := range extensions {
+		ext, err := kitty.ParseExtension(spec)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Extensions = append(cfg.Extensions, ext)
+	}
+	return cfg, nil
+}
+
+// play runs a session with cfg and records its stats. It returns errors
+// rather than exiting, so the log file is closed on every path.
+func play(ctx context.Context, cfg kitty.KittyConfig) error {
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		cfg.Logger = slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	k, err := kitty.New(cfg)
+	if err != nil {
+		return err
+	}
+	k.Start(ctx)
+
+	stats := k.Stats()
+	if statsFile == "" {
+		fmt.Print(stats.Summary())
+		return nil
+	}
+	return kitty.AppendStats(statsFile, stats)
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -75,19 +268,94 @@ func init() {
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
+	initFlags(rootCmd)
+}
+
+// initFlags defines the flags of the root command on cmd.
+func initFlags(cmd *cobra.Command) {
 	defaults := kitty.DefaultKittyConfig()
-	rootCmd.Flags().IntVar(&snakeCount, "snakes", defaults.SnakeCount, "Number of snakes")
-	rootCmd.Flags().IntVar(&snakeMaxLen, "snake-max-len", defaults.SnakeConfig.MaxLen, "Snake max length")
-	rootCmd.Flags().IntVar(&snakeInitialDelayMax, "snake-initial-delay-max", defaults.SnakeConfig.InitialDelayMax, "Max initial delay (ticks) for snakes")
-	rootCmd.Flags().IntVar(&stringCount, "strings", defaults.SwayStringCount, "Number of sway strings")
-	rootCmd.Flags().IntVar(&stringMinLen, "string-min-len", defaults.SwayStringConfig.MinLen, "Sway string min length")
-	rootCmd.Flags().IntVar(&stringMaxLen, "string-max-len", defaults.SwayStringConfig.MaxLen, "Sway string max length")
-	rootCmd.Flags().IntVar(&stringInitialDelayMax, "string-initial-delay-max", defaults.SwayStringConfig.InitialDelayMax, "Max initial delay (ticks) for sway strings")
-	rootCmd.Flags().IntVar(&butterflyCount, "butterflies", defaults.ButterflyCount, "Number of butterflies")
-	rootCmd.Flags().IntVar(&butterflyInitialDelayMax, "butterfly-initial-delay-max", defaults.ButterflyConfig.InitialDelayMax, "Max initial delay (ticks) for butterflies")
-	rootCmd.Flags().IntVar(&laserCount, "lasers", defaults.LaserCount, "Number of laser pointers")
-	rootCmd.Flags().IntVar(&laserInitialDelayMax, "laser-initial-delay-max", defaults.LaserConfig.InitialDelayMax, "Max initial delay (ticks) for lasers")
-	rootCmd.Flags().IntVar(&spiderCount, "spiders", defaults.SpiderCount, "Number of spiders")
-	rootCmd.Flags().IntVar(&spiderInitialDelayMax, "spider-initial-delay-max", defaults.SpiderConfig.InitialDelayMax, "Max initial delay (ticks) for spiders")
-	rootCmd.Flags().BoolVar(&laserHitsSpiders, "laser-hits-spiders", defaults.LaserHitsSpiders, "Allow lasers to destroy spiders")
+	cmd.Flags().IntVar(&snakeCount, "snakes", defaults.SnakeCount, "Number of snakes")
+	cmd.Flags().IntVar(&snakeMaxLen, "snake-max-len", defaults.SnakeConfig.MaxLen, "Snake max length")
+	cmd.Flags().IntVar(&snakeStartLen, "snake-start-len", defaults.SnakeConfig.StartLen, "Length a snake grows to before it has eaten (0 = snake max length)")
+	cmd.Flags().IntVar(&snakeInitialDelayMax, "snake-initial-delay-max", defaults.SnakeConfig.InitialDelayMax, "Max initial delay (ticks) for snakes")
+	cmd.Flags().Float64Var(&snakeSeek, "snake-seek", defaults.SnakeConfig.SeekWeight, "How strongly snakes chase butterflies (negative disables)")
+	cmd.Flags().Float64Var(&snakeFlee, "snake-flee", defaults.SnakeConfig.FleeWeight, "How strongly snakes flee the laser dot (negative disables)")
+	cmd.Flags().Float64Var(&snakeSeparation, "snake-separation", defaults.SnakeConfig.SeparationWeight, "How strongly snakes keep clear of each other (negative disables)")
+	cmd.Flags().Float64Var(&snakeWebAvoid, "snake-web-avoid", defaults.SnakeConfig.WebWeight, "How strongly snakes steer around webs (negative disables)")
+	cmd.Flags().Float64Var(&snakeEatChance, "snake-eat-chance", defaults.SnakeConfig.EatChance, "Chance (0-1) a snake eats a butterfly it reaches")
+	cmd.Flags().IntVar(&stringCount, "strings", defaults.SwayStringCount, "Number of sway strings")
+	cmd.Flags().IntVar(&stringMinLen, "string-min-len", defaults.SwayStringConfig.MinLen, "Sway string min length")
+	cmd.Flags().IntVar(&stringMaxLen, "string-max-len", defaults.SwayStringConfig.MaxLen, "Sway string max length")
+	cmd.Flags().IntVar(&stringInitialDelayMax, "string-initial-delay-max", defaults.SwayStringConfig.InitialDelayMax, "Max initial delay (ticks) for sway strings")
+	cmd.Flags().IntVar(&butterflyCount, "butterflies", defaults.ButterflyCount, "Number of butterflies")
+	cmd.Flags().IntVar(&butterflyInitialDelayMax, "butterfly-initial-delay-max", defaults.ButterflyConfig.InitialDelayMax, "Max initial delay (ticks) for butterflies")
+	cmd.Flags().IntVar(&mouseCount, "mice", defaults.MouseCount, "Number of mice")
+	cmd.Flags().IntVar(&mouseInitialDelayMax, "mouse-initial-delay-max", defaults.MouseConfig.InitialDelayMax, "Max initial delay (ticks) for mice")
+	cmd.Flags().Float64Var(&mouseWebChance, "mouse-web-chance", defaults.MouseConfig.WebChance, "Chance (0-1) a mouse running into a web gets caught")
+	cmd.Flags().IntVar(&fishCount, "fish", defaults.FishCount, "Number of fish schools")
+	cmd.Flags().IntVar(&fishSize, "fish-size", defaults.FishConfig.Size, "Fish per school")
+	cmd.Flags().Float64Var(&fishSpeed, "fish-speed", defaults.FishConfig.Speed, "Fish cruising speed in columns per tick")
+	cmd.Flags().IntVar(&fishInitialDelayMax, "fish-initial-delay-max", defaults.FishConfig.InitialDelayMax, "Max initial delay (ticks) for fish schools")
+	cmd.Flags().IntVar(&wandCount, "wands", defaults.WandCount, "Number of feather wands")
+	cmd.Flags().IntVar(&wandLength, "wand-length", defaults.WandConfig.Length, "Feather wand string length in segments")
+	cmd.Flags().Float64Var(&wandStiffness, "wand-stiffness", defaults.WandConfig.Stiffness, "How stiff the wand string is (0-1)")
+	cmd.Flags().Float64Var(&wandDamping, "wand-damping", defaults.WandConfig.Damping, "How quickly the wand string stops swinging (0-1)")
+	cmd.Flags().IntVar(&wandInitialDelayMax, "wand-initial-delay-max", defaults.WandConfig.InitialDelayMax, "Max initial delay (ticks) for feather wands")
+	cmd.Flags().IntVar(&yarnCount, "yarn", defaults.YarnCount, "Number of yarn balls")
+	cmd.Flags().IntVar(&yarnLength, "yarn-length", defaults.YarnConfig.Length, "Columns of yarn a ball unspools before it runs out")
+	cmd.Flags().Float64Var(&yarnFriction, "yarn-friction", defaults.YarnConfig.Friction, "Share of its speed a yarn ball keeps per tick on the floor (0-1)")
+	cmd.Flags().IntVar(&yarnInitialDelayMax, "yarn-initial-delay-max", defaults.YarnConfig.InitialDelayMax, "Max initial delay (ticks) for yarn balls")
+	cmd.Flags().IntVar(&flyCount, "flies", defaults.FlyCount, "Number of flies")
+	cmd.Flags().IntVar(&flyInitialDelayMax, "fly-initial-delay-max", defaults.FlyConfig.InitialDelayMax, "Max initial delay (ticks) for flies")
+	cmd.Flags().IntVar(&fireflyCount, "fireflies", defaults.FireflyCount, "Number of fireflies")
+	cmd.Flags().IntVar(&fireflyInitialDelayMax, "firefly-initial-delay-max", defaults.FireflyConfig.InitialDelayMax, "Max initial delay (ticks) for fireflies")
+	cmd.Flags().IntVar(&birdCount, "birds", defaults.BirdCount, "Number of birds")
+	cmd.Flags().IntVar(&birdInitialDelayMax, "bird-initial-delay-max", defaults.BirdConfig.InitialDelayMax, "Max initial delay (ticks) for birds")
+	cmd.Flags().Float64Var(&birdDiveChance, "bird-dive-chance", defaults.BirdConfig.DiveChance, "Chance (0-1) per tick a bird dives at a butterfly it sees")
+	cmd.Flags().IntVar(&laserCount, "lasers", defaults.LaserCount, "Number of laser pointers")
+	cmd.Flags().IntVar(&laserInitialDelayMax, "laser-initial-delay-max", defaults.LaserConfig.InitialDelayMax, "Max initial delay (ticks) for lasers")
+	cmd.Flags().DurationVar(&laserCatchAfter, "laser-catch-after", defaults.LaserConfig.CatchAfter, "Land the laser for a catch every time this much play has passed (0 = only on wind-down or the c key)")
+	cmd.Flags().Float64Var(&laserSpeed, "laser-speed", defaults.LaserConfig.Speed, "Laser speed multiplier (below 1 is slower)")
+	cmd.Flags().IntVar(&spiderCount, "spiders", defaults.SpiderCount, "Number of spiders")
+	cmd.Flags().IntVar(&spiderInitialDelayMax, "spider-initial-delay-max", defaults.SpiderConfig.InitialDelayMax, "Max initial delay (ticks) for spiders")
+	cmd.Flags().StringVar(&spiderWebShape, "spider-web", defaults.SpiderConfig.WebShape, "Web shape: orb, spiral, cobweb, corner or random")
+	cmd.Flags().IntVar(&spiderWebRadius, "spider-web-radius", defaults.SpiderConfig.WebRadius, "Web radius in rows (0 = random)")
+	cmd.Flags().IntVar(&spiderWebSpokes, "spider-web-spokes", defaults.SpiderConfig.WebSpokes, "Web spokes or anchor lines (0 = random)")
+	cmd.Flags().IntVar(&spiderWebRings, "spider-web-rings", defaults.SpiderConfig.WebRings, "Web rings, spiral turns or sheet threads (0 = random)")
+	cmd.Flags().BoolVar(&laserHitsSpiders, "laser-hits-spiders", defaults.LaserHitsSpiders, "Allow lasers to destroy spiders")
+	cmd.Flags().StringVar(&backgroundColor, "background", "", "Screen background color, e.g. navy or \"#102030\" (default: the terminal's)")
+	cmd.Flags().StringVar(&groundColor, "ground", "", "Color of a band of grass or sand along the bottom, e.g. green")
+	cmd.Flags().IntVar(&groundRows, "ground-rows", defaults.Background.GroundRows, "Height of the ground band in rows (used with --ground)")
+	cmd.Flags().StringVar(&palette, "palette", defaults.Background.Palette, "Color palette: default or dark (dims webs and critters)")
+	cmd.Flags().StringVar(&sceneName, "scene", "", "Start from a preset scene (see go-kitty scenes); flags given with it change the scene")
+	cmd.Flags().DurationVar(&sessionDuration, "duration", defaults.Session.Duration, "Total session length before winding down and exiting (0 = forever)")
+	cmd.Flags().DurationVar(&sessionPlay, "play", defaults.Session.Play, "Length of each play period (used with --rest)")
+	cmd.Flags().DurationVar(&sessionRest, "rest", defaults.Session.Rest, "Length of each rest period (used with --play)")
+	cmd.Flags().StringVar(&statsFile, "stats-file", "", "Append session stats as a JSON line to this file instead of printing a summary")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", defaults.MetricsAddr, "Serve Prometheus metrics on this address, e.g. \":9090\"")
+	cmd.Flags().StringVar(&logFile, "log-file", "", "Write structured debug logs of critter state changes to this file")
+	cmd.Flags().StringVar(&layers, "layers", "", "Move playthings to other layers, e.g. laser=effects,spider=webs")
+	cmd.Flags().StringVar(&spritesDir, "sprites", "", "Directory of .sprite files that replace or add critter looks, e.g. ~/.config/go-kitty/sprites/")
+	cmd.Flags().StringArrayVar(&scripts, "script", nil, "Starlark critter script to run; repeat for more")
+	cmd.Flags().StringArrayVar(&extensions, "extension", nil, "Options for a registered plaything, e.g. comet:count=2,speed=1.5; repeat for more")
+	cmd.Flags().IntVar(&maxParticles, "max-particles", defaults.MaxParticles, "Most effect particles on screen at once (-1 turns effects off)")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "Random seed for the playthings (0 seeds from the clock)")
+	cmd.Flags().BoolVar(&debug, "debug", false, "Draw each critter's state, velocity and target plus FPS and object counts")
+	cmd.Flags().StringVar(&sessionSchedule, "schedule", defaults.Session.Schedule, "Daily play windows, e.g. \"08:00-08:20,18:00-18:30\"")
+	cmd.Flags().Float64Var(&intensity, "intensity", defaults.Intensity, "Play intensity from just above 0 (calm) to 1 (frantic)")
+	cmd.Flags().StringVar(&intensityCurve, "intensity-curve", "", "Ramp intensity as warmup,peak,cooldown durations, e.g. \"2m,6m,2m\"")
+}
+
+// expandHome expands a leading "~/" to the home directory, for paths passed
+// as --flag=~/dir where the shell leaves the tilde alone.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
 }
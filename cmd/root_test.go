@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v3/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestBuildConfigFlagsWinOverScene(t *testing.T) {
+	for _, args := range [][]string{
+		{"--scene", "night", "--snakes", "1", "--background", "navy"},
+		{"--snakes", "1", "--background", "navy", "--scene", "night"},
+	} {
+		cmd := &cobra.Command{Use: "go-kitty"}
+		initFlags(cmd)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := buildConfig(cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.SnakeCount != 1 || cfg.Background.Color != color.Navy {
+			t.Errorf("%v: snakes %d, background %v; want the flags' 1 and navy", args, cfg.SnakeCount, cfg.Background.Color)
+		}
+		if cfg.FireflyCount != 6 || cfg.Background.Palette != "dark" || cfg.ButterflyCount != 0 {
+			t.Errorf("%v: lost the night scene: %+v", args, cfg)
+		}
+	}
+}
+
+// TestBuildConfigHandlesEveryFlag catches a flag added without a way into
+// the config.
+func TestBuildConfigHandlesEveryFlag(t *testing.T) {
+	handled := map[string]bool{
+		"scene": true, "stats-file": true, "log-file": true, "script": true, "extension": true,
+		"background": true, "ground": true, "intensity-curve": true, "layers": true,
+	}
+	rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if _, ok := flagFields[f.Name]; !ok && !handled[f.Name] {
+			t.Errorf("--%s is not applied to the config", f.Name)
+		}
+	})
+}
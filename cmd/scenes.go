@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sblackstone/go-kitty/kitty"
+	"github.com/spf13/cobra"
+)
+
+var scenesCmd = &cobra.Command{
+	Use:   "scenes",
+	Short: "List the preset scenes",
+	Long:  `List the preset scenes --scene can start from`,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, sc := range kitty.Scenes() {
+			fmt.Printf("%-10s %s\n", sc.Name, sc.Description)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scenesCmd)
+}
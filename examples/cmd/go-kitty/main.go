@@ -0,0 +1,14 @@
+// Command go-kitty is go-kitty built with the example playthings in this
+// module. Each extension package registers itself when imported, so adding
+// one is a matter of adding its import here.
+package main
+
+import (
+	"github.com/sblackstone/go-kitty/cmd"
+
+	_ "github.com/sblackstone/go-kitty/examples/comet"
+)
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,160 @@
+// Package comet is an example of a plaything that lives outside go-kitty.
+// Importing it registers a comet that streaks across the top of the screen
+// trailing a fading tail, until the laser shoots it down.
+//
+// It takes three options: speed in columns per tick, tail length in cells
+// and color, e.g. --extension comet:count=2,speed=1.5,tail=12,color=aqua.
+package comet
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+	"github.com/sblackstone/go-kitty/kitty"
+)
+
+func init() {
+	kitty.Register("comet", New)
+}
+
+// tailRunes fade from the head of the comet to the end of its tail.
+var tailRunes = []rune{'*', '+', '-', '.', '.', '·'}
+
+// Comet crosses the sky on a shallow slope, then waits a while before the
+// next pass.
+type Comet struct {
+	kitty.Lifecycle
+	Speed float64
+	Tail  int
+	Color tcell.Color
+
+	x     float64
+	y     float64
+	vx    float64
+	vy    float64
+	trail []kitty.Point
+	world *kitty.World
+}
+
+// New makes a comet from its options. It is the comet's kitty.Factory.
+func New(opts kitty.Options) (kitty.KittyPlayThing, error) {
+	speed, err := opts.Float("speed", 1.0)
+	if err != nil {
+		return nil, err
+	}
+	if speed <= 0 {
+		return nil, fmt.Errorf("comet: speed must be positive")
+	}
+	tail, err := opts.Int("tail", 8)
+	if err != nil {
+		return nil, err
+	}
+	if tail < 1 {
+		return nil, fmt.Errorf("comet: tail must be at least 1")
+	}
+	name := opts.String("color", "yellow")
+	col := color.GetColor(strings.ToLower(name))
+	if col == color.Default {
+		return nil, fmt.Errorf("comet: unknown color %q", name)
+	}
+	return &Comet{
+		Lifecycle: kitty.Lifecycle{InitialDelayMax: 100},
+		Speed:     speed,
+		Tail:      tail,
+		Color:     col,
+	}, nil
+}
+
+func (c *Comet) Name() string {
+	return "comet"
+}
+
+func (c *Comet) Layer() kitty.Layer {
+	return kitty.LayerCritters
+}
+
+func (c *Comet) Spawn(w *kitty.World) {
+	c.world = w
+	c.Start()
+	c.trail = c.trail[:0]
+	c.x = -1
+	c.vx = c.Speed
+	if w.Intn(2) == 0 {
+		c.x = float64(w.Width)
+		c.vx = -c.vx
+	}
+	c.y = w.Range(0, math.Max(1, float64(w.Height)/3))
+	c.vy = w.Range(0.05, 0.25) * c.Speed
+}
+
+func (c *Comet) Despawn(reason kitty.DespawnReason) {
+	wait := 0
+	if reason != kitty.DespawnRemoved && c.world != nil {
+		wait = 80 + c.world.Intn(200)
+	}
+	c.trail = c.trail[:0]
+	c.Stop(wait)
+}
+
+func (c *Comet) Update(w *kitty.World) {
+	c.world = w
+	if !c.Tick(c, w) {
+		return
+	}
+	c.x += c.vx
+	c.y += c.vy
+	head := kitty.Point{X: int(math.Round(c.x)), Y: int(math.Round(c.y))}
+	if len(c.trail) == 0 || c.trail[0] != head {
+		c.trail = append([]kitty.Point{head}, c.trail...)
+		if len(c.trail) > c.Tail {
+			c.trail = c.trail[:c.Tail]
+		}
+	}
+	// gone once the whole tail has left the screen
+	end := c.trail[len(c.trail)-1]
+	if end.X < -1 || end.X > w.Width || end.Y >= w.Height {
+		c.Despawn(kitty.DespawnGone)
+	}
+}
+
+// HitPoint is the head of the comet.
+func (c *Comet) HitPoint(width, height int) (int, int, bool) {
+	if !c.Active() || len(c.trail) == 0 {
+		return 0, 0, false
+	}
+	p := c.trail[0]
+	if p.X < 0 || p.Y < 0 || p.X >= width || p.Y >= height {
+		return 0, 0, false
+	}
+	return p.X, p.Y, true
+}
+
+// LaserTarget lets the laser shoot the comet whenever it is on screen.
+func (c *Comet) LaserTarget() bool {
+	return c.Active()
+}
+
+func (c *Comet) Bounds() kitty.Rect {
+	var r kitty.Rect
+	if !c.Active() {
+		return r
+	}
+	for _, p := range c.trail {
+		r = r.Union(kitty.Rect{X: p.X, Y: p.Y, Width: 1, Height: 1})
+	}
+	return r
+}
+
+func (c *Comet) Draw(cv *kitty.Canvas) {
+	if !c.Active() {
+		return
+	}
+	// draw the tail first so the head lands on top where they meet
+	for i := len(c.trail) - 1; i >= 0; i-- {
+		fade := i * len(tailRunes) / max(1, c.Tail)
+		cv.Set(c.trail[i].X, c.trail[i].Y, tailRunes[min(fade, len(tailRunes)-1)], c.Color)
+	}
+}
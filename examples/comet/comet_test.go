@@ -0,0 +1,73 @@
+package comet_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sblackstone/go-kitty/examples/comet"
+	"github.com/sblackstone/go-kitty/kitty"
+)
+
+func TestCometRegisters(t *testing.T) {
+	if !slices.Contains(kitty.Registered(), "comet") {
+		t.Fatalf("Registered() = %v, want comet after importing the package", kitty.Registered())
+	}
+}
+
+func TestCometCrossesAndIsShot(t *testing.T) {
+	p, err := comet.New(kitty.Options{Name: "comet", Count: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := p.(*comet.Comet)
+	w := kitty.NewWorld(1)
+	w.Width, w.Height = 80, 24
+	c.Spawn(w)
+
+	shot := false
+	for i := 0; i < 200 && c.Active(); i++ {
+		c.Update(w)
+		if x, y, ok := c.HitPoint(w.Width, w.Height); ok && !shot {
+			if !c.LaserTarget() {
+				t.Fatal("comet on screen is not a laser target")
+			}
+			if b := c.Bounds(); x < b.X || x >= b.X+b.Width || y < b.Y || y >= b.Y+b.Height {
+				t.Fatalf("head %d,%d is outside the bounds %+v", x, y, b)
+			}
+			shot = true
+			c.Despawn(kitty.DespawnHit)
+		}
+	}
+	if !shot {
+		t.Fatal("comet never came on screen")
+	}
+	if c.Active() || c.LaserTarget() {
+		t.Error("comet is still flying after it was shot")
+	}
+	if _, _, ok := c.HitPoint(w.Width, w.Height); ok {
+		t.Error("shot comet can still be hit")
+	}
+}
+
+func TestNewRejectsBadCometOptions(t *testing.T) {
+	for spec, want := range map[string]string{
+		"comet:speed=0":     "speed must be positive",
+		"comet:tail=0":      "tail must be at least 1",
+		"comet:color=plaid": "unknown color",
+		"comet:speed=fast":  "speed must be a number",
+		"comet:count=-1":    "count must not be negative",
+		"meteor:speed=2":    "unknown plaything",
+	} {
+		ext, err := kitty.ParseExtension(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// bad options fail before New starts the screen, so this needs no
+		// terminal
+		_, err = kitty.New(kitty.KittyConfig{Extensions: []kitty.ExtensionConfig{ext}})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("New with extension %q: err = %v, want %q", spec, err, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package kitty
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// groundRunes are the tufts along the top of the ground band.
+var groundRunes = []rune{'"', ',', '\'', '.', '"', '`', ','}
+
+// ParseColor looks up a color by name, e.g. "navy", or as "#rrggbb". An
+// empty name is the terminal's default color.
+func ParseColor(name string) (tcell.Color, error) {
+	if name == "" || name == "default" {
+		return tcell.ColorDefault, nil
+	}
+	col := color.GetColor(strings.ToLower(name))
+	if col == color.Default {
+		return tcell.ColorDefault, fmt.Errorf("unknown color %q", name)
+	}
+	return col, nil
+}
+
+// ValidatePalette reports whether name is a palette the screen can be drawn
+// in. An empty name is the default palette.
+func ValidatePalette(name string) error {
+	switch name {
+	case "", "default", "dark":
+		return nil
+	}
+	return fmt.Errorf("unknown palette %q (want default or dark)", name)
+}
+
+// drawGround fills the bottom rows of the background layer with the ground
+// band: a row of tufts on top of solid ground. The tufts depend only on the
+// column, so the grass holds still while everything plays over it.
+func (k *Kitty) drawGround(c *Canvas) {
+	bg := k.config.Background
+	if bg.GroundRows <= 0 || bg.GroundColor == tcell.ColorDefault {
+		return
+	}
+	width, height := c.Size()
+	top := height - bg.GroundRows
+	tuft := tcell.StyleDefault.Foreground(bg.GroundColor)
+	solid := tcell.StyleDefault.Background(bg.GroundColor)
+	for x := 0; x < width; x++ {
+		h := uint32(x) * 2654435761
+		c.SetContent(x, top, groundRunes[int(h>>16)%len(groundRunes)], tuft)
+		for y := top + 1; y < height; y++ {
+			c.SetContent(x, y, ' ', solid)
+		}
+	}
+}
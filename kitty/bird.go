@@ -0,0 +1,393 @@
+package kitty
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Bird glides across the top third of the screen, flapping now and then. It
+// dives at butterflies below it, flees when a laser fires and can get
+// tangled in a web big enough to hold it.
+type Bird struct {
+	Lifecycle
+	Color tcell.Color
+
+	state      birdState
+	stateTicks int
+	x          float64
+	y          float64
+	vx         float64
+	dir        int
+	cruiseY    float64
+	flapPhase  float64
+	glideTicks int
+	diveChance float64
+	diveWait   int
+	targetX    float64
+	targetY    float64
+	stuckTicks int
+	intensity  float64
+	log        *slog.Logger
+	sprite     *Sprite
+	world      *World
+}
+
+type birdState int
+
+// A bird glides along until it spots a butterfly, dives for it and climbs
+// back to its cruising height, fed or not. Laser fire sends it fleeing off
+// screen.
+const (
+	birdGlide birdState = iota
+	birdDive
+	birdClimb
+	birdFlee
+	birdTangled
+)
+
+func (s birdState) String() string {
+	switch s {
+	case birdGlide:
+		return "glide"
+	case birdDive:
+		return "dive"
+	case birdClimb:
+		return "climb"
+	case birdFlee:
+		return "flee"
+	case birdTangled:
+		return "tangled"
+	}
+	return "unknown"
+}
+
+const (
+	// birdDiveRange is how far ahead, in columns, a bird spots butterflies.
+	birdDiveRange = 30
+	// birdWebHold is how many web threads must cover a bird to hold it.
+	birdWebHold = 8
+)
+
+func (b *Bird) setState(state birdState) {
+	if b.state == state {
+		return
+	}
+	b.log.Debug("bird state", "from", b.state.String(), "to", state.String(),
+		"x", math.Round(b.x), "y", math.Round(b.y))
+	b.state = state
+	b.stateTicks = 0
+}
+
+func (b *Bird) Name() string {
+	return "bird"
+}
+
+func (b *Bird) Layer() Layer {
+	return LayerCritters
+}
+
+func (b *Bird) Update(w *World) {
+	b.world = w
+	width, height := w.Width, w.Height
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if !b.Tick(b, w) {
+		return
+	}
+	b.stateTicks++
+	if b.diveWait > 0 {
+		b.diveWait--
+	}
+	act := activity(b.intensity)
+
+	if len(w.Beams) > 0 && b.state != birdFlee && b.state != birdTangled {
+		b.setState(birdFlee)
+	}
+
+	switch b.state {
+	case birdTangled:
+		b.flapPhase += 1.5
+		b.stuckTicks--
+		if b.stuckTicks <= 0 {
+			b.BreakFree()
+		}
+		return
+	case birdGlide:
+		b.flap()
+		b.x += b.vx * float64(b.dir) * act
+		b.y += (b.cruiseY-b.y)*0.05 + math.Sin(b.flapPhase*0.3)*0.05
+		if !b.Leaving() && b.diveWait == 0 {
+			if p, ok := b.spotButterfly(); ok && b.world.Float64() < b.diveChance*act {
+				b.targetX, b.targetY = float64(p.X), float64(p.Y)
+				b.setState(birdDive)
+			}
+		}
+	case birdDive:
+		p, ok := b.nearestButterfly(b.targetX, b.targetY, 6)
+		if !ok || b.stateTicks > 40 || b.Leaving() {
+			b.setState(birdClimb)
+			break
+		}
+		b.targetX, b.targetY = float64(p.X), float64(p.Y)
+		dx := (b.targetX - b.x) / cellAspect
+		dy := b.targetY - b.y
+		d := math.Max(math.Hypot(dx, dy), 0.001)
+		step := math.Min(1.5*act, d)
+		b.x += dx / d * step * cellAspect
+		b.y += dy / d * step
+		if dx != 0 {
+			b.dir = 1
+			if dx < 0 {
+				b.dir = -1
+			}
+		}
+	case birdClimb:
+		b.flap()
+		b.x += b.vx * float64(b.dir) * act
+		b.y -= 0.5 * act
+		if b.y <= b.cruiseY {
+			b.y = b.cruiseY
+			b.diveWait = calmWait(60+b.world.Intn(60), b.intensity)
+			b.setState(birdGlide)
+		}
+	case birdFlee:
+		b.flapPhase += 1.2
+		b.vx = math.Min(b.vx+0.2, 3)
+		b.x += b.vx * float64(b.dir) * act
+		b.y = math.Max(b.y-0.4, 1)
+	}
+
+	if b.x < -5 || b.x > float64(width+4) {
+		b.Despawn(DespawnGone)
+	}
+}
+
+// flap beats the wings for a while, then glides with them held level.
+func (b *Bird) flap() {
+	if b.glideTicks > 0 {
+		b.glideTicks--
+		return
+	}
+	b.flapPhase += 0.6
+	if math.Mod(b.flapPhase, 4*math.Pi) < 0.6 && b.world.Float64() < 0.5 {
+		b.glideTicks = 10 + b.world.Intn(30)
+	}
+}
+
+// spotButterfly looks for a free butterfly below and ahead of the bird.
+func (b *Bird) spotButterfly() (Point, bool) {
+	best := Point{}
+	bestD := math.MaxFloat64
+	for _, p := range b.world.Butterflies {
+		dx := (float64(p.X) - b.x) * float64(b.dir)
+		dy := float64(p.Y) - b.y
+		if dx <= 0 || dx > birdDiveRange || dy <= 0 {
+			continue
+		}
+		if d := math.Hypot(dx/cellAspect, dy); d < bestD {
+			best, bestD = p, d
+		}
+	}
+	return best, bestD < math.MaxFloat64
+}
+
+// nearestButterfly finds the free butterfly closest to (x, y), within
+// radius rows, so a diving bird can follow its target.
+func (b *Bird) nearestButterfly(x, y, radius float64) (Point, bool) {
+	best := Point{}
+	bestD := radius
+	found := false
+	for _, p := range b.world.Butterflies {
+		if d := math.Hypot((float64(p.X)-x)/cellAspect, float64(p.Y)-y); d <= bestD {
+			best, bestD, found = p, d, true
+		}
+	}
+	return best, found
+}
+
+// Diving reports whether the bird is swooping at a butterfly.
+func (b *Bird) Diving() bool {
+	return b.Active() && b.state == birdDive
+}
+
+// Ate ends a dive after the bird caught its butterfly.
+func (b *Bird) Ate() {
+	b.log.Debug("bird ate butterfly", "x", math.Round(b.x), "y", math.Round(b.y))
+	b.setState(birdClimb)
+}
+
+func (b *Bird) Spawn(w *World) {
+	b.world = w
+	b.Start()
+	b.sprite = w.Sprites.Pick("bird", w)
+	b.dir = 1
+	b.x = -4
+	if b.world.Intn(2) == 0 {
+		b.dir = -1
+		b.x = float64(w.Width + 3)
+	}
+	b.cruiseY = b.world.Range(1, math.Max(1, float64(w.Height)/3))
+	b.y = b.cruiseY
+	b.vx = b.world.Range(0.8, 1.3)
+	b.flapPhase = b.world.Range(0, math.Pi*2)
+	b.glideTicks = 0
+	b.diveWait = 20
+	b.stuckTicks = 0
+	b.state = birdGlide
+	b.stateTicks = 0
+}
+
+// Despawn takes the bird off screen.
+func (b *Bird) Despawn(reason DespawnReason) {
+	x, y, ok := b.HitPoint(math.MaxInt32, math.MaxInt32)
+	wait := 0
+	switch reason {
+	case DespawnGone:
+		wait = calmWait(150+b.world.Intn(250), b.intensity)
+	case DespawnHit:
+		if ok {
+			b.world.Effects.Emit(fxBurst, float64(x), float64(y))
+		}
+		wait = 100 + b.world.Intn(200)
+	case DespawnEaten:
+		b.log.Debug("bird eaten", "x", x, "y", y)
+		if ok {
+			b.world.Effects.Emit(fxCaught, float64(x), float64(y))
+		}
+		wait = calmWait(200+b.world.Intn(300), b.intensity)
+	}
+	b.state = birdGlide
+	b.Stop(wait)
+}
+
+// HitPoint is the bird's body, the middle of its sprite.
+func (b *Bird) HitPoint(width, height int) (int, int, bool) {
+	if !b.Active() {
+		return 0, 0, false
+	}
+	x, y := int(math.Round(b.x)), int(math.Round(b.y))
+	if x < 0 || y < 0 || x >= width || y >= height {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+func (b *Bird) Bounds() Rect {
+	if !b.Active() {
+		return Rect{}
+	}
+	return rectAround(int(math.Round(b.x)), int(math.Round(b.y)), 3, 1)
+}
+
+// frame picks the sprite frame for what the bird is doing: wings folded in
+// a dive, held level in a glide and flapping otherwise.
+func (b *Bird) frame() *SpriteFrame {
+	sprite := b.sprite
+	switch b.state {
+	case birdDive:
+		return sprite.Frame("folded")
+	case birdGlide, birdClimb:
+		if b.glideTicks > 0 {
+			return sprite.Frame("level")
+		}
+	}
+	return sprite.At(int(math.Floor(b.flapPhase / math.Pi * 2)))
+}
+
+func (b *Bird) Draw(c *Canvas) {
+	if !b.Active() {
+		return
+	}
+	fg := b.Color
+	if fg == tcell.ColorDefault || fg == 0 {
+		fg = color.Sienna
+	}
+	cx, cy := int(math.Round(b.x)), int(math.Round(b.y))
+	if b.state == birdTangled && b.world.Float64() < 0.5 {
+		cx += b.world.Intn(3) - 1
+	}
+	frame := b.frame()
+	if b.dir < 0 {
+		frame = frame.Flip()
+	}
+	frame.Draw(c, cx, cy, fg, fg)
+}
+
+// StickToWeb tangles the bird only in a web with enough threads across its
+// wings; a small web does not hold it.
+func (b *Bird) StickToWeb() bool {
+	if b.state == birdFlee || b.state == birdTangled {
+		return false
+	}
+	r := b.Bounds()
+	threads := 0
+	for _, p := range b.world.WebPoints {
+		if p.X >= r.X && p.X < r.X+r.Width && p.Y >= r.Y && p.Y < r.Y+r.Height {
+			threads++
+		}
+	}
+	if threads < birdWebHold {
+		return false
+	}
+	b.setState(birdTangled)
+	b.stuckTicks = 100 + b.world.Intn(100)
+	return true
+}
+
+// BreakFree lets a tangled bird fly off.
+func (b *Bird) BreakFree() {
+	if b.state != birdTangled {
+		return
+	}
+	b.setState(birdFlee)
+}
+
+func (b *Bird) IsStuckInWeb() bool {
+	return b.state == birdTangled
+}
+
+// Struggle is strong; a bird tears through webs quickly.
+func (b *Bird) Struggle() float64 {
+	return 0.05
+}
+
+func (b *Bird) SetIntensity(intensity float64) {
+	b.intensity = intensity
+}
+
+func (b *Bird) SetLogger(log *slog.Logger) {
+	b.log = log
+}
+
+func (b *Bird) DebugInfo() (int, int, string, bool) {
+	x, y, ok := b.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return 0, 0, "", false
+	}
+	info := fmt.Sprintf("bird %s vx=%.1f", b.state, b.vx*float64(b.dir))
+	if b.state == birdDive {
+		info += fmt.Sprintf(" tgt=(%.0f,%.0f)", b.targetX, b.targetY)
+	}
+	return x, y, info, true
+}
+
+func NewBird(cfg BirdConfig) *Bird {
+	if cfg.InitialDelayMax <= 0 {
+		cfg.InitialDelayMax = 100
+	}
+	if cfg.DiveChance <= 0 || cfg.DiveChance > 1 {
+		cfg.DiveChance = 0.05
+	}
+	return &Bird{
+		Lifecycle:  Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		Color:      cfg.Color,
+		diveChance: cfg.DiveChance,
+		intensity:  1.0,
+		log:        discardLogger,
+	}
+}
@@ -2,17 +2,13 @@ package kitty
 
 import (
 	"math"
-	"math/rand"
-	"time"
 
 	"github.com/gdamore/tcell/v3"
 	"github.com/gdamore/tcell/v3/color"
 )
 
 type BouncyBall struct {
-	active      bool
-	respawnWait int
-	initDelaySet bool
+	Lifecycle
 
 	x           float64
 	y           float64
@@ -27,31 +23,49 @@ type BouncyBall struct {
 	twitchTicks int
 	wigglePhase float64
 	wiggleAmp   float64
+	world       *World
 }
 
-var bouncyBallSeeded bool
+// ballGravity is how fast a ball falls, in rows per tick squared.
+const ballGravity = 0.35
 
-func (s *BouncyBall) Update(screen tcell.Screen) {
+// bounce moves a ball at y with vertical speed vy through one tick of
+// gravity. A ball that reaches floor stops there and bounces back up with
+// keep of its speed. It reports whether the ball hit the floor, so the
+// caller can decide how the bouncing dies out.
+func bounce(y, vy, gravity, floor, keep float64) (float64, float64, bool) {
+	vy += gravity
+	y += vy
+	if y < floor {
+		return y, vy, false
+	}
+	return floor, -vy * keep, true
+}
+
+func (s *BouncyBall) Name() string {
+	return "ball"
+}
+
+func (s *BouncyBall) Layer() Layer {
+	return LayerCritters
+}
+
+func (s *BouncyBall) Update(w *World) {
+	s.world = w
 	if s.radius == 0 {
 		s.radius = 3
 	}
 	if s.gravity == 0 {
-		s.gravity = 0.35
-	}
-	if !s.active && !s.initDelaySet {
-		s.respawnWait = rand.Intn(60)
-		s.initDelaySet = true
+		s.gravity = ballGravity
 	}
-	if s.respawnWait > 0 {
-		s.respawnWait--
-		return
+	if s.InitialDelayMax == 0 {
+		s.InitialDelayMax = 59
 	}
-	if !s.active {
-		s.initBall(screen)
+	if !s.Tick(s, w) {
 		return
 	}
 
-	width, height := screen.Size()
+	width, height := w.Width, w.Height
 	ground := float64(height - 1)
 
 	// occasional pause or dart for prey-like movement
@@ -60,67 +74,76 @@ func (s *BouncyBall) Update(screen tcell.Screen) {
 		s.targetVx = 0.1 * float64(s.dir)
 	} else if s.dartTicks > 0 {
 		s.dartTicks--
-		s.targetVx = randRange(4.0, 6.0) * float64(s.dir)
+		s.targetVx = s.world.Range(4.0, 6.0) * float64(s.dir)
 		if s.dartTicks == 0 {
-			s.targetVx = randRange(1.2, 3.0) * float64(s.dir)
+			s.targetVx = s.world.Range(1.2, 3.0) * float64(s.dir)
 		}
 	} else {
 		// subtle speed drift
-		s.targetVx += (rand.Float64() - 0.5) * 0.08
+		s.targetVx += (s.world.Float64() - 0.5) * 0.08
 		s.targetVx = clampFloat(s.targetVx, 0.8, 3.4) * float64(s.dir)
-		if rand.Float64() < 0.015 {
-			s.pauseTicks = 6 + rand.Intn(12)
+		if s.world.Float64() < 0.015 {
+			s.pauseTicks = 6 + s.world.Intn(12)
 		}
-		if rand.Float64() < 0.02 {
-			s.dartTicks = 8 + rand.Intn(14)
+		if s.world.Float64() < 0.02 {
+			s.dartTicks = 8 + s.world.Intn(14)
 		}
 	}
 
 	if s.twitchTicks > 0 {
 		s.twitchTicks--
 		s.targetVx *= 1.15
-	} else if rand.Float64() < 0.01 {
-		s.twitchTicks = 6 + rand.Intn(10)
+	} else if s.world.Float64() < 0.01 {
+		s.twitchTicks = 6 + s.world.Intn(10)
 	}
 
 	s.vx += (s.targetVx - s.vx) * 0.12
 
-	s.vy += s.gravity
 	s.x += s.vx
-	s.y += s.vy
-
-	if s.y+float64(s.radius) >= ground {
-		s.y = ground - float64(s.radius)
-		s.vy = -s.vy * 0.7
+	var landed bool
+	s.y, s.vy, landed = bounce(s.y, s.vy, s.gravity, ground-float64(s.radius), 0.7)
+	if landed {
 		if math.Abs(s.vy) < 0.6 {
-			s.vy = -randRange(2.5, 4.0)
+			s.vy = -s.world.Range(2.5, 4.0)
 		}
-		if rand.Float64() < 0.05 {
-			s.vy = -randRange(2.0, 3.2)
+		if s.world.Float64() < 0.05 {
+			s.vy = -s.world.Range(2.0, 3.2)
 		}
 	}
 
-	if s.dir > 0 && s.x-float64(s.radius) > float64(width) {
-		s.active = false
-		s.respawnWait = 60 + rand.Intn(140)
-		return
+	if (s.dir > 0 && s.x-float64(s.radius) > float64(width)) || (s.dir < 0 && s.x+float64(s.radius) < 0) {
+		s.Despawn(DespawnGone)
 	}
-	if s.dir < 0 && s.x+float64(s.radius) < 0 {
-		s.active = false
-		s.respawnWait = 60 + rand.Intn(140)
-		return
+}
+
+func (s *BouncyBall) Spawn(w *World) {
+	s.world = w
+	s.initBall(w.Width, w.Height)
+}
+
+func (s *BouncyBall) Despawn(reason DespawnReason) {
+	wait := 0
+	if reason != DespawnRemoved {
+		wait = 60 + s.world.Intn(140)
 	}
+	s.Stop(wait)
 }
 
-func (s *BouncyBall) Draw(screen tcell.Screen) {
-	if !s.active {
+func (s *BouncyBall) Bounds() Rect {
+	if !s.Active() {
+		return Rect{}
+	}
+	return rectAround(int(math.Round(s.x)), int(math.Round(s.y)), s.radius+1, s.radius)
+}
+
+func (s *BouncyBall) Draw(c *Canvas) {
+	if !s.Active() {
 		return
 	}
-	width, height := screen.Size()
 	centerX := int(math.Round(s.x))
 	s.wigglePhase += 0.35
-	if rand.Float64() < 0.02 {
-		s.wiggleAmp = randRange(0.0, 0.8)
+	if s.world.Float64() < 0.02 {
+		s.wiggleAmp = s.world.Range(0.0, 0.8)
 	}
 	centerY := int(math.Round(s.y))
 	centerX += int(math.Round(math.Sin(s.wigglePhase) * s.wiggleAmp))
@@ -134,35 +157,24 @@ func (s *BouncyBall) Draw(screen tcell.Screen) {
 			if fx*fx+fy*fy > r*r {
 				continue
 			}
-			x := centerX + dx
-			y := centerY + dy
-			if x < 0 || y < 0 || x >= width || y >= height {
-				continue
-			}
-			screen.SetContent(x, y, tcell.RuneBlock, nil, tcell.StyleDefault.Foreground(fg))
+			c.Set(centerX+dx, centerY+dy, tcell.RuneBlock, fg)
 		}
 	}
 }
 
-func (s *BouncyBall) initBall(screen tcell.Screen) {
-	width, height := screen.Size()
+func (s *BouncyBall) initBall(width, height int) {
 	if width <= 0 || height <= 0 {
 		return
 	}
-	if !bouncyBallSeeded {
-		rand.Seed(time.Now().UnixNano())
-		bouncyBallSeeded = true
-	}
-
-	s.active = true
+	s.Start()
 	s.dir = 1
-	if rand.Intn(2) == 0 {
+	if s.world.Intn(2) == 0 {
 		s.dir = -1
 	}
 	// cross the screen in a few bounces
-	s.vx = randRange(1.8, 3.2) * float64(s.dir)
+	s.vx = s.world.Range(1.8, 3.2) * float64(s.dir)
 	s.targetVx = s.vx
-	s.vy = -randRange(3.0, 5.0)
+	s.vy = -s.world.Range(3.0, 5.0)
 	ground := float64(height - 1)
 	s.y = ground - float64(s.radius)
 	if s.dir > 0 {
@@ -173,6 +185,6 @@ func (s *BouncyBall) initBall(screen tcell.Screen) {
 	s.pauseTicks = 0
 	s.dartTicks = 0
 	s.twitchTicks = 0
-	s.wigglePhase = randRange(0, math.Pi*2)
-	s.wiggleAmp = randRange(0.2, 0.6)
+	s.wigglePhase = s.world.Range(0, math.Pi*2)
+	s.wiggleAmp = s.world.Range(0.2, 0.6)
 }
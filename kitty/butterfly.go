@@ -1,23 +1,21 @@
 package kitty
 
 import (
+	"fmt"
+	"log/slog"
 	"math"
-	"math/rand"
-	"time"
 
 	"github.com/gdamore/tcell/v3"
 	"github.com/gdamore/tcell/v3/color"
 )
 
 type Butterfly struct {
+	Lifecycle
+	prey
 	Color tcell.Color
 
-	active       bool
-	respawnWait  int
-	initDelaySet bool
-	initialDelayMax int
-	stuckInWeb   bool
-	stuckTicks   int
+	intensity    float64
+	sprite       *Sprite
 
 	x         float64
 	baseY     float64
@@ -29,117 +27,130 @@ type Butterfly struct {
 	flutterTicks int
 	burstTicks   int
 	turnBias     float64
-	explosionTicks int
-	explosionX    int
-	explosionY    int
+	dustTicks    int
 }
 
-var butterflyRandSeeded bool
+func (b *Butterfly) Name() string {
+	return "butterfly"
+}
 
-func (b *Butterfly) Update(screen tcell.Screen) {
-	width, height := screen.Size()
-	if width <= 0 || height <= 0 {
-		return
-	}
-	if b.explosionTicks > 0 {
-		b.explosionTicks--
-		return
-	}
-	if !butterflyRandSeeded {
-		rand.Seed(time.Now().UnixNano())
-		butterflyRandSeeded = true
-	}
-	if !b.active && !b.initDelaySet {
-		maxDelay := b.initialDelayMax
-		if maxDelay <= 0 {
-			maxDelay = 80
-		}
-		b.respawnWait = rand.Intn(maxDelay + 1)
-		b.initDelaySet = true
-	}
-	if b.respawnWait > 0 {
-		b.respawnWait--
+func (b *Butterfly) Layer() Layer {
+	return LayerCritters
+}
+
+func (b *Butterfly) Update(w *World) {
+	b.world = w
+	width := w.Width
+	if width <= 0 || w.Height <= 0 {
 		return
 	}
-	if !b.active {
-		b.initButterfly(width, height)
+	if !b.Tick(b, w) {
 		return
 	}
 
-	// Check if stuck in web
-	if b.stuckInWeb {
-		b.stuckTicks--
-		if b.stuckTicks <= 0 {
-			// Escape after struggling
-			b.stuckInWeb = false
-		}
+	// Check if stuck in web; it escapes after struggling
+	if b.held(b) {
 		// Still flap wings while stuck
-		b.flapPhase += 1.2 + rand.Float64()*0.5
+		b.flapPhase += 1.2 + b.world.Float64()*0.5
 		return
 	}
 
 	// flutter and dart behavior for prey-like motion
-	b.wavePhase += 0.18 + rand.Float64()*0.08
-	b.flapPhase += 0.7 + rand.Float64()*0.25
+	b.wavePhase += 0.18 + b.world.Float64()*0.08
+	b.flapPhase += 0.7 + b.world.Float64()*0.25
 
+	act := activity(b.intensity)
 	if b.flutterTicks > 0 {
 		b.flutterTicks--
-		b.vx = randRange(0.3, 0.8)
-		b.waveAmp = clampFloat(b.waveAmp+randRange(-0.15, 0.15), 0.5, 4.0)
-	} else if b.burstTicks > 0 {
+		b.vx = b.world.Range(0.3, 0.8)
+		b.waveAmp = clampFloat(b.waveAmp+b.world.Range(-0.15, 0.15), 0.5, 4.0)
+	} else if b.burstTicks > 0 || b.Leaving() {
 		b.burstTicks--
-		b.vx = randRange(1.6, 2.6)
-		if rand.Float64() < 0.15 {
+		b.vx = b.world.Range(1.6, 2.6)
+		if b.world.Float64() < 0.15 {
 			b.turnBias *= -1
 		}
 	} else {
-		if rand.Float64() < 0.02 {
-			b.flutterTicks = 10 + rand.Intn(18)
+		if b.world.Float64() < 0.02 {
+			b.flutterTicks = 10 + b.world.Intn(18)
 		}
-		if rand.Float64() < 0.02 {
-			b.burstTicks = 6 + rand.Intn(12)
+		if b.world.Float64() < 0.02*act {
+			b.burstTicks = 6 + b.world.Intn(12)
 		}
-		b.vx = clampFloat(b.vx+randRange(-0.08, 0.08), 0.5, 1.6)
+		b.vx = clampFloat(b.vx+b.world.Range(-0.08, 0.08), 0.5, 1.6)
+	}
+
+	if b.world.Float64() < 0.01 {
+		b.turnBias = b.world.Range(-1.0, 1.0)
 	}
 
-	if rand.Float64() < 0.01 {
-		b.turnBias = randRange(-1.0, 1.0)
+	b.x += b.vx * act * float64(b.dir)
+	b.dropDust()
+
+	if (b.dir > 0 && b.x > float64(width+2)) || (b.dir < 0 && b.x < -2) {
+		b.Despawn(DespawnGone)
 	}
+}
 
-	b.x += b.vx * float64(b.dir)
+func (b *Butterfly) Spawn(w *World) {
+	b.world = w
+	b.Start()
+	b.sprite = w.Sprites.Pick("butterfly", w)
+	b.initButterfly(w.Width, w.Height)
+}
 
-	if b.dir > 0 && b.x > float64(width+2) {
-		b.active = false
-		b.respawnWait = 40 + rand.Intn(80)
+// dropDust leaves a faint trail of wing dust every few ticks.
+func (b *Butterfly) dropDust() {
+	b.dustTicks--
+	if b.dustTicks > 0 {
 		return
 	}
-	if b.dir < 0 && b.x < -2 {
-		b.active = false
-		b.respawnWait = 40 + rand.Intn(80)
+	b.dustTicks = 2 + b.world.Intn(3)
+	x, y, ok := b.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
 		return
 	}
+	fx := fxWingDust
+	if b.Color != tcell.ColorDefault && b.Color != 0 {
+		fx.Ramp = []tcell.Color{b.Color, color.Gray, color.DarkGray}
+	}
+	b.world.Effects.Emit(fx, float64(x-b.dir), float64(y))
 }
 
-func (b *Butterfly) Draw(screen tcell.Screen) {
-	if b.explosionTicks > 0 {
-		b.drawExplosion(screen)
-		return
+// Despawn takes the butterfly off screen. A lasered butterfly bursts where
+// it was hit.
+func (b *Butterfly) Despawn(reason DespawnReason) {
+	b.despawned(b, reason)
+	wait := 0
+	switch reason {
+	case DespawnGone:
+		wait = calmWait(40+b.world.Intn(80), b.intensity)
+	case DespawnHit:
+		wait = 40 + b.world.Intn(80)
+	case DespawnEaten:
+		wait = calmWait(80+b.world.Intn(120), b.intensity)
 	}
-	if !b.active {
-		return
+	b.Stop(wait)
+}
+
+func (b *Butterfly) Bounds() Rect {
+	x, y, ok := b.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return Rect{}
 	}
-	width, height := screen.Size()
-	cx := int(math.Round(b.x))
-	wobble := math.Sin(b.wavePhase*1.7) * 0.8
-	cy := int(math.Round(b.baseY + math.Sin(b.wavePhase)*b.waveAmp + wobble))
+	return rectAround(x, y, 2, 2)
+}
 
-	if cx < 0 || cy < 0 || cx >= width || cy >= height {
+func (b *Butterfly) Draw(c *Canvas) {
+	width, height := c.Size()
+	cx, cy, ok := b.HitPoint(width, height)
+	if !ok {
 		return
 	}
 
 	fg := b.Color
 	if fg == tcell.ColorDefault || fg == 0 {
-		fg = randomButterflyColor()
+		fg = randomButterflyColor(b.world)
 		b.Color = fg
 	}
 
@@ -148,42 +159,18 @@ func (b *Butterfly) Draw(screen tcell.Screen) {
 		bright = color.Aqua
 	}
 
-	open := math.Sin(b.flapPhase+b.turnBias) > 0
-	if open {
-		// open wings
-		b.drawCell(screen, cx-1, cy-1, '\\', fg, width, height)
-		b.drawCell(screen, cx+1, cy-1, '/', fg, width, height)
-		b.drawCell(screen, cx-1, cy+1, '/', fg, width, height)
-		b.drawCell(screen, cx+1, cy+1, '\\', fg, width, height)
-
-		b.drawCell(screen, cx-2, cy-2, '\\', bright, width, height)
-		b.drawCell(screen, cx+2, cy-2, '/', bright, width, height)
-		b.drawCell(screen, cx-2, cy+2, '/', bright, width, height)
-		b.drawCell(screen, cx+2, cy+2, '\\', bright, width, height)
-	} else {
-		// closed wings
-		b.drawCell(screen, cx-1, cy-1, '/', fg, width, height)
-		b.drawCell(screen, cx+1, cy-1, '\\', fg, width, height)
-		b.drawCell(screen, cx-1, cy+1, '\\', fg, width, height)
-		b.drawCell(screen, cx+1, cy+1, '/', fg, width, height)
-
-		b.drawCell(screen, cx-2, cy-2, '/', bright, width, height)
-		b.drawCell(screen, cx+2, cy-2, '\\', bright, width, height)
-		b.drawCell(screen, cx-2, cy+2, '\\', bright, width, height)
-		b.drawCell(screen, cx+2, cy+2, '/', bright, width, height)
+	frame := b.sprite.Frame("closed")
+	if math.Sin(b.flapPhase+b.turnBias) > 0 {
+		frame = b.sprite.Frame("open")
 	}
-}
-
-func (b *Butterfly) Hit(x, y int) {
-	b.active = false
-	b.explosionTicks = 6
-	b.explosionX = x
-	b.explosionY = y
-	b.respawnWait = 40 + rand.Intn(80)
+	if b.dir < 0 {
+		frame = frame.Flip()
+	}
+	frame.Draw(c, cx, cy, fg, bright)
 }
 
 func (b *Butterfly) HitPoint(width, height int) (int, int, bool) {
-	if !b.active {
+	if !b.Active() {
 		return 0, 0, false
 	}
 	cx := int(math.Round(b.x))
@@ -195,46 +182,18 @@ func (b *Butterfly) HitPoint(width, height int) (int, int, bool) {
 	return cx, cy, true
 }
 
-func (b *Butterfly) drawExplosion(screen tcell.Screen) {
-	width, height := screen.Size()
-	fg := color.Yellow
-	if b.explosionTicks <= 2 {
-		fg = color.Red
-	}
-	center := Point{X: b.explosionX, Y: b.explosionY}
-	for _, p := range []Point{
-		center,
-		{X: center.X - 1, Y: center.Y},
-		{X: center.X + 1, Y: center.Y},
-		{X: center.X, Y: center.Y - 1},
-		{X: center.X, Y: center.Y + 1},
-		{X: center.X - 1, Y: center.Y - 1},
-		{X: center.X + 1, Y: center.Y - 1},
-		{X: center.X - 1, Y: center.Y + 1},
-		{X: center.X + 1, Y: center.Y + 1},
-	} {
-		if p.X < 0 || p.Y < 0 || p.X >= width || p.Y >= height {
-			continue
-		}
-		screen.SetContent(p.X, p.Y, tcell.RuneBullet, nil, tcell.StyleDefault.Foreground(fg))
-	}
-}
-
-func (b *Butterfly) drawCell(screen tcell.Screen, x, y int, r rune, fg tcell.Color, width, height int) {
-	if x < 0 || y < 0 || x >= width || y >= height {
-		return
-	}
-	screen.SetContent(x, y, r, nil, tcell.StyleDefault.Foreground(fg))
+// LaserTarget lets the laser shoot the butterfly whenever it is on screen.
+func (b *Butterfly) LaserTarget() bool {
+	return b.Active()
 }
 
 func (b *Butterfly) initButterfly(width, height int) {
-	b.active = true
-	b.wavePhase = randRange(0, math.Pi*2)
-	b.flapPhase = randRange(0, math.Pi*2)
-	b.waveAmp = randRange(0.5, 2.5)
-	b.vx = randRange(0.6, 1.4)
+	b.wavePhase = b.world.Range(0, math.Pi*2)
+	b.flapPhase = b.world.Range(0, math.Pi*2)
+	b.waveAmp = b.world.Range(0.5, 2.5)
+	b.vx = b.world.Range(0.6, 1.4)
 	b.dir = 1
-	if rand.Intn(2) == 0 {
+	if b.world.Intn(2) == 0 {
 		b.dir = -1
 	}
 	minY := 1
@@ -242,7 +201,7 @@ func (b *Butterfly) initButterfly(width, height int) {
 	if maxY < minY {
 		maxY = minY
 	}
-	b.baseY = float64(minY + rand.Intn(maxY-minY+1))
+	b.baseY = float64(minY + b.world.Intn(maxY-minY+1))
 	if b.dir > 0 {
 		b.x = -2
 	} else {
@@ -250,13 +209,13 @@ func (b *Butterfly) initButterfly(width, height int) {
 	}
 	b.flutterTicks = 0
 	b.burstTicks = 0
-	b.turnBias = randRange(-1.0, 1.0)
-	b.Color = randomButterflyColor()
-	b.stuckInWeb = false
+	b.turnBias = b.world.Range(-1.0, 1.0)
+	b.Color = randomButterflyColor(b.world)
+	b.stuck = false
 	b.stuckTicks = 0
 }
 
-func randomButterflyColor() tcell.Color {
+func randomButterflyColor(w *World) tcell.Color {
 	colors := []tcell.Color{
 		color.Fuchsia,
 		color.Purple,
@@ -266,22 +225,52 @@ func randomButterflyColor() tcell.Color {
 		color.Lime,
 		color.White,
 	}
-	return colors[rand.Intn(len(colors))]
+	return colors[w.Intn(len(colors))]
 }
 
-func (b *Butterfly) StickToWeb() {
-	b.stuckInWeb = true
-	b.stuckTicks = 60 + rand.Intn(80) // Stuck for 60-140 ticks
+func (b *Butterfly) StickToWeb() bool {
+	b.stick(b.x, b.baseY, 60+b.world.Intn(80)) // Stuck for 60-140 ticks
+	return true
 }
 
-func (b *Butterfly) IsStuckInWeb() bool {
-	return b.stuckInWeb
+func (b *Butterfly) Struggle() float64 {
+	return 0.02
 }
 
-func (b *Butterfly) BeEaten() {
-	b.active = false
-	b.stuckInWeb = false
-	b.respawnWait = 80 + rand.Intn(120)
+// BreakFree releases a butterfly whose struggling tore the web around it.
+func (b *Butterfly) BreakFree() {
+	b.free(b.x, b.baseY)
+}
+
+// Leave makes the butterfly dart off the edge it is heading for. A butterfly
+// stuck in a web leaves once it struggles free or is eaten.
+func (b *Butterfly) Leave() {
+	b.Lifecycle.Leave()
+	b.flutterTicks = 0
+}
+
+func (b *Butterfly) SetIntensity(intensity float64) {
+	b.intensity = intensity
+}
+
+func (b *Butterfly) SetLogger(log *slog.Logger) {
+	b.log = log
+}
+
+func (b *Butterfly) DebugInfo() (int, int, string, bool) {
+	x, y, ok := b.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return 0, 0, "", false
+	}
+	state := "fly"
+	if b.stuck {
+		state = fmt.Sprintf("stuck(%d)", b.stuckTicks)
+	} else if b.burstTicks > 0 {
+		state = "burst"
+	} else if b.flutterTicks > 0 {
+		state = "flutter"
+	}
+	return x, y, fmt.Sprintf("butterfly %s vx=%.1f", state, b.vx*float64(b.dir)), true
 }
 
 func NewButterfly(cfg ButterflyConfig) *Butterfly {
@@ -289,8 +278,9 @@ func NewButterfly(cfg ButterflyConfig) *Butterfly {
 		cfg.InitialDelayMax = 80
 	}
 	return &Butterfly{
-		Color:           cfg.Color,
-		initialDelayMax: cfg.InitialDelayMax,
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		prey:      prey{name: "butterfly", log: discardLogger},
+		Color:     cfg.Color,
+		intensity: 1.0,
 	}
 }
-
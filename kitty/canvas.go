@@ -0,0 +1,94 @@
+package kitty
+
+import (
+	"github.com/gdamore/tcell/v3"
+)
+
+// Canvas is the cell buffer playthings draw into. Every cell remembers the
+// layer it was drawn on: a cell on a higher layer is never covered by one on
+// a lower layer, and within a layer the last draw wins. Kitty draws in a
+// fixed order, so overlaps resolve the same way every frame.
+//
+// A cell drawn without a background keeps the one of the cell it covers, so
+// critters walking over the ground stay on it, and falls back to the screen
+// background.
+type Canvas struct {
+	width  int
+	height int
+	cells  []canvasCell
+	layer  Layer
+	// background fills cells nothing below gave a background.
+	background tcell.Color
+	// dark dims the webs and critters layers.
+	dark bool
+}
+
+type canvasCell struct {
+	r     rune
+	style tcell.Style
+	layer Layer
+	set   bool
+}
+
+// reset clears the canvas and resizes it to the screen.
+func (c *Canvas) reset(width, height int) {
+	c.width = width
+	c.height = height
+	n := max(0, width*height)
+	if cap(c.cells) < n {
+		c.cells = make([]canvasCell, n)
+	}
+	c.cells = c.cells[:n]
+	clear(c.cells)
+}
+
+func (c *Canvas) Size() (int, int) {
+	return c.width, c.height
+}
+
+// SetLayer switches the layer following draws land on and returns the
+// previous one, so a plaything can draw part of itself elsewhere, like a
+// spider's web below the spider.
+func (c *Canvas) SetLayer(l Layer) Layer {
+	prev := c.layer
+	c.layer = l
+	return prev
+}
+
+// SetContent draws one cell on the current layer. Cells off screen are
+// skipped.
+func (c *Canvas) SetContent(x, y int, r rune, style tcell.Style) {
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return
+	}
+	cell := &c.cells[y*c.width+x]
+	if cell.set && cell.layer > c.layer {
+		return
+	}
+	if cell.set && style.GetBackground() == tcell.ColorDefault {
+		style = style.Background(cell.style.GetBackground())
+	}
+	*cell = canvasCell{r: r, style: style, layer: c.layer, set: true}
+}
+
+// Set draws one cell in the given foreground color.
+func (c *Canvas) Set(x, y int, r rune, fg tcell.Color) {
+	c.SetContent(x, y, r, tcell.StyleDefault.Foreground(fg))
+}
+
+// flush copies every drawn cell to the screen.
+func (c *Canvas) flush(s tcell.Screen) {
+	for i, cell := range c.cells {
+		if !cell.set {
+			continue
+		}
+		style := cell.style
+		if style.GetBackground() == tcell.ColorDefault {
+			style = style.Background(c.background)
+		}
+		if c.dark && (cell.layer == LayerWebs || cell.layer == LayerCritters) {
+			style = style.Dim(true)
+		}
+		s.SetContent(i%c.width, i/c.width, cell.r, nil, style)
+	}
+}
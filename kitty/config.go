@@ -1,11 +1,29 @@
 package kitty
 
-import "github.com/gdamore/tcell/v3"
+import (
+	"log/slog"
+	"time"
 
+	"github.com/gdamore/tcell/v3"
+)
+
+// SnakeConfig weights steer a snake: SeekWeight pulls it toward the nearest
+// butterfly, FleeWeight pushes it away from laser dots, SeparationWeight
+// away from other snakes and WebWeight away from webs. Zero takes the
+// default weight and a negative one turns the behavior off. EatChance (0-1)
+// is how likely a snake is to eat a butterfly it reaches, growing one
+// segment up to MaxLen. StartLen is how long a snake grows on its own after
+// spawning; 0 means MaxLen.
 type SnakeConfig struct {
-	MaxLen          int
-	Color           tcell.Color
-	InitialDelayMax int
+	MaxLen           int
+	StartLen         int
+	Color            tcell.Color
+	InitialDelayMax  int
+	SeekWeight       float64
+	FleeWeight       float64
+	SeparationWeight float64
+	WebWeight        float64
+	EatChance        float64
 }
 
 type SwayStringConfig struct {
@@ -13,6 +31,7 @@ type SwayStringConfig struct {
 	MaxLen          int
 	Color           tcell.Color
 	InitialDelayMax int
+	Speed           float64
 }
 
 type ButterflyConfig struct {
@@ -20,14 +39,111 @@ type ButterflyConfig struct {
 	InitialDelayMax int
 }
 
+// MouseConfig.WebChance (0-1) is how likely a mouse running into a web is
+// caught in it.
+type MouseConfig struct {
+	Color           tcell.Color
+	InitialDelayMax int
+	WebChance       float64
+}
+
+// FishConfig.Size is how many fish swim in each school and Speed their
+// cruising speed in columns per tick; they go faster when scattering.
+type FishConfig struct {
+	Color           tcell.Color
+	InitialDelayMax int
+	Size            int
+	Speed           float64
+}
+
+// FeatherWandConfig.Length is the number of one-row segments in the string.
+// Stiffness (0-1] is how much of its stretch each segment takes back per pass
+// and Damping (0-1) how much speed the string loses per tick.
+type FeatherWandConfig struct {
+	Color           tcell.Color
+	InitialDelayMax int
+	Length          int
+	Stiffness       float64
+	Damping         float64
+}
+
+// YarnBallConfig.Length is how many columns of yarn a ball unspools before
+// it runs out, and Friction (0-1] how much of its speed it keeps per tick
+// while rolling along the floor.
+type YarnBallConfig struct {
+	Color           tcell.Color
+	InitialDelayMax int
+	Length          int
+	Friction        float64
+}
+
+type FlyConfig struct {
+	Color           tcell.Color
+	InitialDelayMax int
+}
+
+// FireflyConfig.Color is the color of a fully lit firefly; by default it
+// glows through greens and yellows.
+type FireflyConfig struct {
+	Color           tcell.Color
+	InitialDelayMax int
+}
+
+// BirdConfig.DiveChance (0-1) is how likely a bird is to dive, each tick, at
+// a butterfly it can see below it; zero takes the default.
+type BirdConfig struct {
+	Color           tcell.Color
+	InitialDelayMax int
+	DiveChance      float64
+}
+
+// LaserConfig.LandX and LandY place the catch landing spot as fractions of
+// the screen's width and height in cells: 0, 0 is the top-left cell and
+// 0.5, 1.0 the bottom center cell. InitialDelayMax is in ticks. CatchAfter
+// lands the lasers every time that much play has passed (0 disables). Speed
+// scales how fast the dot roams and dashes, in cells per tick.
 type LaserConfig struct {
 	Color           tcell.Color
 	InitialDelayMax int
+	LandX           float64
+	LandY           float64
+	CatchAfter      time.Duration
+	Speed           float64
 }
 
+// SpiderConfig.WebShape is one of "orb", "spiral", "cobweb", "corner" or
+// "random". WebRadius (in rows), WebSpokes and WebRings size each web; zero
+// picks a random size per web.
 type SpiderConfig struct {
 	Color           tcell.Color
 	InitialDelayMax int
+	WebShape        string
+	WebRadius       int
+	WebSpokes       int
+	WebRings        int
+}
+
+// BackgroundConfig is the backdrop the playthings play in front of. Color
+// fills the screen; the default leaves the terminal's own. GroundRows rows
+// of GroundColor grass or sand run along the bottom, if GroundColor is set.
+// Palette is "default" or "dark", which dims webs and critters for a night
+// scene.
+type BackgroundConfig struct {
+	Color       tcell.Color
+	GroundColor tcell.Color
+	GroundRows  int
+	Palette     string
+}
+
+// SessionConfig limits how long and when the cat gets to play. Play and Rest
+// alternate and must be set together. Schedule is a comma separated list of
+// daily "HH:MM-HH:MM" play windows; the session ends with the window that
+// starts last in the day.
+type SessionConfig struct {
+	Duration time.Duration
+	Play     time.Duration
+	Rest     time.Duration
+	Schedule string
 }
 
 type KittyConfig struct {
@@ -37,18 +153,63 @@ type KittyConfig struct {
 	SwayStringConfig SwayStringConfig
 	ButterflyCount   int
 	ButterflyConfig  ButterflyConfig
+	MouseCount       int
+	MouseConfig      MouseConfig
+	FishCount        int
+	FishConfig       FishConfig
+	WandCount        int
+	WandConfig       FeatherWandConfig
+	YarnCount        int
+	YarnConfig       YarnBallConfig
+	FlyCount         int
+	FlyConfig        FlyConfig
+	FireflyCount     int
+	FireflyConfig    FireflyConfig
+	BirdCount        int
+	BirdConfig       BirdConfig
 	LaserCount       int
 	LaserConfig      LaserConfig
 	SpiderCount      int
 	SpiderConfig     SpiderConfig
 	LaserHitsSpiders bool
+	Background       BackgroundConfig
+	Session          SessionConfig
+	// Intensity (0-1) scales how lively the playthings are, from calm to
+	// frantic; 0 means 1.
+	Intensity      float64
+	IntensityCurve IntensityCurve
+	MetricsAddr    string
+	Logger         *slog.Logger
+	Debug          bool
+	// Layers moves plaything types to other layers, by type name; see
+	// ParseLayers.
+	Layers map[string]Layer
+	// Seed seeds the random source shared by the playthings; zero seeds from
+	// the clock.
+	Seed int64
+	// MaxParticles caps how many effect particles are on screen at once;
+	// zero means the default and a negative value turns effects off.
+	MaxParticles int
+	// SpritesDir is a directory of .sprite files that replace the built-in
+	// looks or add variants; empty uses only the built-in sprites.
+	SpritesDir string
+	// Scripts are the paths of Starlark critter scripts to run.
+	Scripts []string
+	// Extensions configures playthings other packages registered.
+	// Registered types left out run once with no options.
+	Extensions []ExtensionConfig
 }
 
 func DefaultSnakeConfig() SnakeConfig {
 	return SnakeConfig{
-		MaxLen:          10,
-		Color:           tcell.ColorDefault,
-		InitialDelayMax: 40,
+		MaxLen:           10,
+		Color:            tcell.ColorDefault,
+		InitialDelayMax:  40,
+		SeekWeight:       0.4,
+		FleeWeight:       1.0,
+		SeparationWeight: 0.6,
+		WebWeight:        1.0,
+		EatChance:        0.3,
 	}
 }
 
@@ -58,6 +219,7 @@ func DefaultSwayStringConfig() SwayStringConfig {
 		MaxLen:          36,
 		Color:           tcell.ColorDefault,
 		InitialDelayMax: 40,
+		Speed:           1.0,
 	}
 }
 
@@ -68,10 +230,71 @@ func DefaultButterflyConfig() ButterflyConfig {
 	}
 }
 
+func DefaultMouseConfig() MouseConfig {
+	return MouseConfig{
+		Color:           tcell.ColorDefault,
+		InitialDelayMax: 60,
+		WebChance:       0.2,
+	}
+}
+
+func DefaultFishConfig() FishConfig {
+	return FishConfig{
+		Color:           tcell.ColorDefault,
+		InitialDelayMax: 60,
+		Size:            6,
+		Speed:           0.8,
+	}
+}
+
+func DefaultFeatherWandConfig() FeatherWandConfig {
+	return FeatherWandConfig{
+		Color:           tcell.ColorDefault,
+		InitialDelayMax: 50,
+		Length:          12,
+		Stiffness:       0.9,
+		Damping:         0.02,
+	}
+}
+
+func DefaultYarnBallConfig() YarnBallConfig {
+	return YarnBallConfig{
+		Color:           tcell.ColorDefault,
+		InitialDelayMax: 70,
+		Length:          60,
+		Friction:        0.97,
+	}
+}
+
+func DefaultFlyConfig() FlyConfig {
+	return FlyConfig{
+		Color:           tcell.ColorDefault,
+		InitialDelayMax: 60,
+	}
+}
+
+func DefaultFireflyConfig() FireflyConfig {
+	return FireflyConfig{
+		Color:           tcell.ColorDefault,
+		InitialDelayMax: 80,
+	}
+}
+
+func DefaultBirdConfig() BirdConfig {
+	return BirdConfig{
+		Color:           tcell.ColorDefault,
+		InitialDelayMax: 100,
+		DiveChance:      0.05,
+	}
+}
+
 func DefaultLaserConfig() LaserConfig {
 	return LaserConfig{
 		Color:           tcell.ColorDefault,
 		InitialDelayMax: 80,
+		LandX:           0.5,
+		LandY:           1.0,
+		Speed:           1.0,
 	}
 }
 
@@ -79,6 +302,16 @@ func DefaultSpiderConfig() SpiderConfig {
 	return SpiderConfig{
 		Color:           tcell.ColorDefault,
 		InitialDelayMax: 60,
+		WebShape:        "random",
+	}
+}
+
+func DefaultBackgroundConfig() BackgroundConfig {
+	return BackgroundConfig{
+		Color:       tcell.ColorDefault,
+		GroundColor: tcell.ColorDefault,
+		GroundRows:  2,
+		Palette:     "default",
 	}
 }
 
@@ -90,10 +323,27 @@ func DefaultKittyConfig() KittyConfig {
 		SwayStringConfig: DefaultSwayStringConfig(),
 		ButterflyCount:   1,
 		ButterflyConfig:  DefaultButterflyConfig(),
+		MouseCount:       0,
+		MouseConfig:      DefaultMouseConfig(),
+		FishCount:        0,
+		FishConfig:       DefaultFishConfig(),
+		WandCount:        0,
+		WandConfig:       DefaultFeatherWandConfig(),
+		YarnCount:        0,
+		YarnConfig:       DefaultYarnBallConfig(),
+		FlyCount:         0,
+		FlyConfig:        DefaultFlyConfig(),
+		FireflyCount:     0,
+		FireflyConfig:    DefaultFireflyConfig(),
+		BirdCount:        0,
+		BirdConfig:       DefaultBirdConfig(),
 		LaserCount:       1,
 		LaserConfig:      DefaultLaserConfig(),
 		SpiderCount:      1,
 		SpiderConfig:     DefaultSpiderConfig(),
 		LaserHitsSpiders: false,
+		Background:       DefaultBackgroundConfig(),
+		Intensity:        1.0,
+		MaxParticles:     defaultMaxParticles,
 	}
 }
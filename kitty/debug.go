@@ -0,0 +1,86 @@
+package kitty
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// KittyLoggable is implemented by playthings that log their state
+// transitions. Kitty hands every plaything its logger when it spawns.
+type KittyLoggable interface {
+	SetLogger(log *slog.Logger)
+}
+
+// KittyDebugger is implemented by playthings that can describe themselves for
+// the debug overlay. It returns where the plaything is and a one line
+// summary of its state, velocity and target.
+type KittyDebugger interface {
+	DebugInfo() (x, y int, info string, ok bool)
+}
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+var debugStyle = tcell.StyleDefault.Foreground(color.Silver).Background(color.Black)
+
+// fpsCounter measures frames per second over a sliding one second window.
+type fpsCounter struct {
+	windowStart time.Time
+	frames      int
+	fps         int
+}
+
+func (f *fpsCounter) tick(now time.Time) {
+	if f.windowStart.IsZero() {
+		f.windowStart = now
+	}
+	f.frames++
+	if elapsed := now.Sub(f.windowStart); elapsed >= time.Second {
+		f.fps = int(float64(f.frames) / elapsed.Seconds())
+		f.frames = 0
+		f.windowStart = now
+	}
+}
+
+// drawDebugOverlay labels every plaything with its debug info and puts FPS
+// and object counts in the top left corner.
+func (k *Kitty) drawDebugOverlay() {
+	counts := map[string]int{}
+	for _, o := range k.objects {
+		counts[o.Name()]++
+		d, ok := o.(KittyDebugger)
+		if !ok {
+			continue
+		}
+		x, y, info, ok := d.DebugInfo()
+		if !ok {
+			continue
+		}
+		drawText(&k.canvas, x+3, y, info, debugStyle)
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := []string{fmt.Sprintf("fps %d", k.fps.fps), k.phase.String()}
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %d", name, counts[name]))
+	}
+	parts = append(parts, fmt.Sprintf("intensity %.2f", k.Intensity))
+	parts = append(parts, fmt.Sprintf("particles %d", k.effects.Len()))
+	drawText(&k.canvas, 0, 0, strings.Join(parts, "  "), debugStyle)
+}
+
+func drawText(c *Canvas, x, y int, text string, style tcell.Style) {
+	for _, r := range text {
+		c.SetContent(x, y, r, style)
+		x++
+	}
+}
@@ -0,0 +1,228 @@
+package kitty
+
+import (
+	"math"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Effect describes a kind of particle: how many are emitted at once, how
+// fast they fly off, how long they live and how they look as they age. Runes
+// and Ramp are stepped through evenly over a particle's life.
+type Effect struct {
+	Runes   []rune
+	Ramp    []tcell.Color
+	Count   int
+	Life    int
+	Speed   float64
+	Gravity float64
+	Drag    float64
+	// Ambient effects such as trails are only emitted while less than half
+	// the particle budget is in use, leaving room for bursts.
+	Ambient bool
+}
+
+var (
+	// fxBurst is what is left of something the laser shot.
+	fxBurst = Effect{
+		Runes: []rune{'*', '•', tcell.RuneBullet},
+		Ramp:  []tcell.Color{color.Yellow, color.Orange, color.Red, color.Maroon},
+		Count: 12,
+		Life:  7,
+		Speed: 1.2,
+		Drag:  0.2,
+	}
+	// fxCaught marks a butterfly being eaten.
+	fxCaught = Effect{
+		Runes: []rune{'+', '×', tcell.RuneBullet},
+		Ramp:  []tcell.Color{color.White, color.Aqua, color.Teal},
+		Count: 8,
+		Life:  6,
+		Speed: 0.8,
+		Drag:  0.25,
+	}
+	// fxSpark flies off the laser dot when it fires.
+	fxSpark = Effect{
+		Runes:   []rune{'\'', '`', '.'},
+		Ramp:    []tcell.Color{color.Yellow, color.Red, color.DarkRed},
+		Count:   4,
+		Life:    4,
+		Speed:   1.5,
+		Gravity: 0.15,
+	}
+	// fxWingDust trails behind a butterfly; its ramp starts at the
+	// butterfly's color.
+	fxWingDust = Effect{
+		Runes:   []rune{'.', tcell.RuneBullet},
+		Ramp:    []tcell.Color{color.Silver, color.Gray, color.DarkGray},
+		Count:   1,
+		Life:    8,
+		Speed:   0.1,
+		Gravity: 0.05,
+		Ambient: true,
+	}
+	// fxSlitherDust is kicked up behind a snake's tail.
+	fxSlitherDust = Effect{
+		Runes:   []rune{',', '.'},
+		Ramp:    []tcell.Color{color.Gray, color.DarkSlateGray},
+		Count:   1,
+		Life:    5,
+		Speed:   0.3,
+		Drag:    0.3,
+		Ambient: true,
+	}
+	// fxWebSnap is loose silk drifting down from a strand that broke.
+	fxWebSnap = Effect{
+		Runes:   []rune{'~', '\'', '.'},
+		Ramp:    []tcell.Color{color.Gray, color.DarkGray, color.DarkSlateGray},
+		Count:   4,
+		Life:    12,
+		Speed:   0.5,
+		Gravity: 0.08,
+		Drag:    0.15,
+	}
+	// fxBubble rises from the bottom of a fish tank.
+	fxBubble = Effect{
+		Runes:   []rune{'.', 'o', 'O', 'o'},
+		Ramp:    []tcell.Color{color.Teal, color.Aqua, color.LightCyan},
+		Count:   1,
+		Life:    30,
+		Speed:   0.05,
+		Gravity: -0.05,
+		Drag:    0.1,
+		Ambient: true,
+	}
+	// fxCaughtText floats up from where the laser was caught.
+	fxCaughtText = Effect{
+		Ramp: []tcell.Color{color.Yellow, color.Orange, color.Maroon},
+		Life: 24,
+	}
+)
+
+const defaultMaxParticles = 200
+
+// fxDefaultRune is how particles look when their effect has no Runes.
+const fxDefaultRune = '.'
+
+type particle struct {
+	x, y   float64
+	vx, vy float64
+	age    int
+	r      rune
+	fx     *Effect
+}
+
+// Effects runs every particle on screen. It is owned by Kitty and reached
+// through World.Effects. The number of live particles never exceeds the
+// budget, so effects cannot crowd out the critters. A nil *Effects emits
+// nothing.
+type Effects struct {
+	particles []particle
+	budget    int
+	world     *World
+}
+
+func newEffects(budget int, w *World) *Effects {
+	return &Effects{budget: budget, world: w}
+}
+
+func (e *Effects) room(fx *Effect) int {
+	if e == nil {
+		return 0
+	}
+	limit := e.budget
+	if fx.Ambient {
+		limit /= 2
+	}
+	return max(0, limit-len(e.particles))
+}
+
+// Emit sends fx's particles flying from (x, y) in random directions.
+func (e *Effects) Emit(fx Effect, x, y float64) {
+	n := min(fx.Count, e.room(&fx))
+	if n <= 0 {
+		return
+	}
+	shared := &fx
+	for i := 0; i < n; i++ {
+		angle := e.world.Range(0, 2*math.Pi)
+		speed := fx.Speed * e.world.Range(0.3, 1)
+		e.particles = append(e.particles, particle{
+			x:  x,
+			y:  y,
+			vx: math.Cos(angle) * speed * cellAspect,
+			vy: math.Sin(angle) * speed,
+			fx: shared,
+		})
+	}
+}
+
+// EmitText floats text up from (x, y), centered, one particle per letter.
+func (e *Effects) EmitText(fx Effect, text string, x, y float64) {
+	runes := []rune(text)
+	if len(runes) > e.room(&fx) {
+		return
+	}
+	shared := &fx
+	x -= float64(len(runes)) / 2
+	for i, r := range runes {
+		e.particles = append(e.particles, particle{
+			x:  x + float64(i),
+			y:  y,
+			vy: -0.15,
+			r:  r,
+			fx: shared,
+		})
+	}
+}
+
+// Len is the number of live particles.
+func (e *Effects) Len() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.particles)
+}
+
+func (e *Effects) Update() {
+	if e == nil {
+		return
+	}
+	live := e.particles[:0]
+	for _, p := range e.particles {
+		p.age++
+		if p.age >= p.fx.Life {
+			continue
+		}
+		p.x += p.vx
+		p.y += p.vy
+		p.vy += p.fx.Gravity
+		p.vx *= 1 - p.fx.Drag
+		p.vy *= 1 - p.fx.Drag
+		live = append(live, p)
+	}
+	clear(e.particles[len(live):])
+	e.particles = live
+}
+
+func (e *Effects) Draw(c *Canvas) {
+	if e == nil {
+		return
+	}
+	for _, p := range e.particles {
+		u := float64(p.age) / float64(max(1, p.fx.Life))
+		r := p.r
+		if r == 0 {
+			r = fxDefaultRune
+			if n := len(p.fx.Runes); n > 0 {
+				r = p.fx.Runes[min(int(u*float64(n)), n-1)]
+			}
+		}
+		fg := tcell.ColorDefault
+		if n := len(p.fx.Ramp); n > 0 {
+			fg = p.fx.Ramp[min(int(u*float64(n)), n-1)]
+		}
+		c.Set(int(math.Round(p.x)), int(math.Round(p.y)), r, fg)
+	}
+}
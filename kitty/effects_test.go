@@ -0,0 +1,28 @@
+package kitty
+
+import (
+	"testing"
+)
+
+func TestEffectsDrawWithoutRunesOrRamp(t *testing.T) {
+	w := NewWorld(1)
+	e := newEffects(10, w)
+	e.Emit(Effect{Count: 3, Life: 5}, 5, 5)
+	e.EmitText(Effect{Life: 5}, "hi", 5, 8)
+
+	var c Canvas
+	c.reset(20, 20)
+	e.Draw(&c)
+	drawn := map[rune]int{}
+	for _, cell := range c.cells {
+		if cell.set {
+			drawn[cell.r]++
+		}
+	}
+	if drawn[fxDefaultRune] == 0 {
+		t.Errorf("particles without runes drew %v, want %q", drawn, fxDefaultRune)
+	}
+	if drawn['h'] != 1 || drawn['i'] != 1 {
+		t.Errorf("text particles drew %v, want h and i", drawn)
+	}
+}
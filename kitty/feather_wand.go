@@ -0,0 +1,377 @@
+package kitty
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// FeatherWand is a feather tuft on a string hanging from the top edge, the
+// way a person dangles a wand toy. The string is a Verlet chain: every node
+// keeps its momentum, falls under gravity and is pulled back to one segment
+// from its neighbours, so flicking the anchor sends real swings and waves
+// down to the feather. A mouse drag that starts on the string or the feather
+// picks the wand up and moves the anchor.
+type FeatherWand struct {
+	Lifecycle
+	Color tcell.Color
+
+	nodes        []wandNode
+	segments     int
+	stiffness    float64
+	damping      float64
+	anchorX      float64
+	anchorY      float64
+	targetX      float64
+	flickTicks   int
+	pauseTicks   int
+	lifeTicks    int
+	dragged      bool
+	flutter      float64
+	featherColor tcell.Color
+	intensity    float64
+	log          *slog.Logger
+	sprite       *Sprite
+	world        *World
+}
+
+type wandNode struct {
+	x, y   float64
+	px, py float64
+}
+
+const (
+	// wandGravity is in rows per tick squared.
+	wandGravity = 0.12
+	// wandIterations is how often per tick the segment lengths are
+	// enforced; more is stiffer but slower.
+	wandIterations = 8
+)
+
+var featherColors = []tcell.Color{color.Fuchsia, color.Lime, color.Aqua, color.Yellow, color.Orange}
+
+func (f *FeatherWand) Name() string {
+	return "wand"
+}
+
+func (f *FeatherWand) Layer() Layer {
+	return LayerCritters
+}
+
+func (f *FeatherWand) Update(w *World) {
+	f.world = w
+	width, height := w.Width, w.Height
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if !f.Tick(f, w) {
+		return
+	}
+	if f.lifeTicks > 0 {
+		f.lifeTicks--
+	}
+
+	f.moveAnchor(width)
+	f.integrate()
+	f.constrain()
+	f.flutter += 0.4 + math.Abs(f.tipSpeed())
+
+	if (f.Leaving() || f.lifeTicks == 0) && !f.dragged && f.offScreen() {
+		f.Despawn(DespawnGone)
+	}
+}
+
+// moveAnchor plays the wand like a person would: drift, wait, then flick.
+// A mouse drag that grabbed the wand takes over the anchor completely.
+func (f *FeatherWand) moveAnchor(width int) {
+	w := f.world
+	if w.DragStarted && w.Grabbed == nil && f.near(w.DragStart) {
+		w.Grabbed = f
+		f.log.Debug("wand grabbed", "x", w.DragStart.X, "y", w.DragStart.Y)
+	}
+	if w.Dragging && w.Grabbed == f {
+		f.dragged = true
+		f.anchorX = float64(w.Drag.X)
+		f.anchorY = float64(w.Drag.Y)
+		return
+	}
+	if f.dragged {
+		f.dragged = false
+		f.targetX = f.anchorX
+	}
+
+	if f.Leaving() || f.lifeTicks == 0 {
+		// lift the wand off the top of the screen
+		f.anchorY -= 0.6
+		return
+	}
+	// a dragged wand drifts back up to the top edge
+	f.anchorY += (0 - f.anchorY) * 0.1
+
+	act := activity(f.intensity)
+	if f.pauseTicks > 0 {
+		f.pauseTicks--
+		return
+	}
+	if f.flickTicks > 0 {
+		f.flickTicks--
+		return
+	}
+	f.anchorX += clampFloat(f.targetX-f.anchorX, -0.8, 0.8) * act
+	if math.Abs(f.targetX-f.anchorX) < 1 || f.world.Float64() < 0.01 {
+		f.targetX = f.world.Range(2, math.Max(2, float64(width-3)))
+	}
+	switch {
+	case f.world.Float64() < 0.02*act:
+		f.flick(width)
+	case f.world.Float64() < 0.01/act:
+		f.pauseTicks = 10 + f.world.Intn(30)
+	}
+}
+
+// flick jerks the anchor sideways and whips the end of the string, a short
+// impulse that travels down the chain.
+func (f *FeatherWand) flick(width int) {
+	dir := 1.0
+	if f.world.Intn(2) == 0 {
+		dir = -1
+	}
+	jerk := f.world.Range(2, 6) * dir
+	f.anchorX = clampFloat(f.anchorX+jerk, 0, float64(width-1))
+	kick := f.world.Range(0.5, 1.5) * activity(f.intensity)
+	for i := len(f.nodes) / 2; i < len(f.nodes); i++ {
+		n := &f.nodes[i]
+		n.px -= dir * kick * cellAspect
+		n.py += kick * 0.5
+	}
+	f.flickTicks = 3 + f.world.Intn(5)
+	f.log.Debug("wand flick", "x", math.Round(f.anchorX), "dir", dir)
+}
+
+// integrate moves every free node by its velocity plus gravity.
+func (f *FeatherWand) integrate() {
+	if len(f.nodes) == 0 {
+		return
+	}
+	f.nodes[0] = wandNode{x: f.anchorX, y: f.anchorY, px: f.anchorX, py: f.anchorY}
+	keep := 1 - f.damping
+	for i := 1; i < len(f.nodes); i++ {
+		n := &f.nodes[i]
+		vx := (n.x - n.px) * keep
+		vy := (n.y - n.py) * keep
+		n.px, n.py = n.x, n.y
+		n.x += vx
+		n.y += vy + wandGravity
+	}
+}
+
+// constrain pulls neighbouring nodes back to one segment apart. Distances
+// are measured in rows; columns count half as much.
+func (f *FeatherWand) constrain() {
+	for it := 0; it < wandIterations; it++ {
+		for i := 1; i < len(f.nodes); i++ {
+			a := &f.nodes[i-1]
+			b := &f.nodes[i]
+			dx := (b.x - a.x) / cellAspect
+			dy := b.y - a.y
+			d := math.Hypot(dx, dy)
+			if d < 1e-6 {
+				continue
+			}
+			diff := (d - 1) / d * f.stiffness
+			if i == 1 {
+				b.x -= dx * diff * cellAspect
+				b.y -= dy * diff
+				continue
+			}
+			a.x += dx * diff * 0.5 * cellAspect
+			a.y += dy * diff * 0.5
+			b.x -= dx * diff * 0.5 * cellAspect
+			b.y -= dy * diff * 0.5
+		}
+	}
+}
+
+// near reports whether p is on or next to the string or the feather.
+func (f *FeatherWand) near(p Point) bool {
+	points := f.points()
+	if len(points) > 0 {
+		tip := points[len(points)-1]
+		points = append(points, Point{X: tip.X, Y: tip.Y + 1})
+	}
+	for _, q := range points {
+		if absInt(q.X-p.X) <= 2 && absInt(q.Y-p.Y) <= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FeatherWand) tipSpeed() float64 {
+	if len(f.nodes) == 0 {
+		return 0
+	}
+	tip := f.nodes[len(f.nodes)-1]
+	return math.Hypot((tip.x-tip.px)/cellAspect, tip.y-tip.py)
+}
+
+func (f *FeatherWand) offScreen() bool {
+	for _, n := range f.nodes {
+		if n.y >= -1 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *FeatherWand) Spawn(w *World) {
+	f.world = w
+	f.Start()
+	f.sprite = w.Sprites.Pick("wand", w)
+	f.anchorX = f.world.Range(2, math.Max(2, float64(w.Width-3)))
+	f.anchorY = 0
+	f.targetX = f.anchorX
+	// the string starts bunched up above the screen and drops in
+	f.nodes = f.nodes[:0]
+	for i := 0; i <= f.segments; i++ {
+		y := -float64(i) * 0.5
+		f.nodes = append(f.nodes, wandNode{x: f.anchorX, y: y, px: f.anchorX, py: y})
+	}
+	f.flickTicks = 0
+	f.pauseTicks = 20
+	f.lifeTicks = calmWait(500+f.world.Intn(500), f.intensity)
+	f.dragged = false
+	f.featherColor = featherColors[f.world.Intn(len(featherColors))]
+}
+
+func (f *FeatherWand) Despawn(reason DespawnReason) {
+	wait := 0
+	if reason != DespawnRemoved {
+		wait = calmWait(100+f.world.Intn(200), f.intensity)
+	}
+	f.nodes = f.nodes[:0]
+	f.Stop(wait)
+}
+
+func (f *FeatherWand) points() []Point {
+	points := make([]Point, 0, len(f.nodes))
+	for _, n := range f.nodes {
+		points = append(points, Point{X: int(math.Round(n.x)), Y: int(math.Round(n.y))})
+	}
+	return points
+}
+
+// HitPoint is the feather at the end of the string.
+func (f *FeatherWand) HitPoint(width, height int) (int, int, bool) {
+	if !f.Active() || len(f.nodes) == 0 {
+		return 0, 0, false
+	}
+	tip := f.nodes[len(f.nodes)-1]
+	x, y := int(math.Round(tip.x)), int(math.Round(tip.y))
+	if x < 0 || y < 0 || x >= width || y >= height {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+// Bounds covers the string and the feather hanging below its end.
+func (f *FeatherWand) Bounds() Rect {
+	points := f.points()
+	if len(points) == 0 {
+		return Rect{}
+	}
+	tip := points[len(points)-1]
+	return boundsOf(points, 1).Union(rectAround(tip.X, tip.Y+1, 1, 1))
+}
+
+func (f *FeatherWand) Draw(c *Canvas) {
+	points := f.points()
+	if len(points) == 0 {
+		return
+	}
+	fg := f.Color
+	if fg == tcell.ColorDefault || fg == 0 {
+		fg = color.Silver
+	}
+	for i := 1; i < len(points); i++ {
+		drawChainLink(c, points[i-1], points[i], fg)
+	}
+	tip := points[len(points)-1]
+	frame := "still"
+	if math.Sin(f.flutter) > 0 {
+		frame = "flutter"
+	}
+	// the feather hangs below the end of the string
+	f.sprite.Frame(frame).Draw(c, tip.X, tip.Y+1, f.featherColor, f.featherColor)
+}
+
+// drawChainLink draws the string between two nodes with a rune that follows
+// its slope.
+func drawChainLink(c *Canvas, a, b Point, fg tcell.Color) {
+	var r rune
+	dx := float64(b.X-a.X) / cellAspect
+	dy := float64(b.Y - a.Y)
+	switch {
+	case math.Abs(dx) > 2*math.Abs(dy):
+		r = '-'
+	case math.Abs(dy) > 2*math.Abs(dx):
+		r = '|'
+	case (dx > 0) == (dy > 0):
+		r = '\\'
+	default:
+		r = '/'
+	}
+	drawLaserBeam(c, a.X, a.Y, b.X, b.Y, r, fg)
+}
+
+func (f *FeatherWand) SetIntensity(intensity float64) {
+	f.intensity = intensity
+}
+
+func (f *FeatherWand) SetLogger(log *slog.Logger) {
+	f.log = log
+}
+
+func (f *FeatherWand) DebugInfo() (int, int, string, bool) {
+	x, y, ok := f.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return 0, 0, "", false
+	}
+	state := "dangle"
+	switch {
+	case f.dragged:
+		state = "dragged"
+	case f.flickTicks > 0:
+		state = "flick"
+	case f.pauseTicks > 0:
+		state = "pause"
+	}
+	return x, y, fmt.Sprintf("wand %s v=%.1f", state, f.tipSpeed()), true
+}
+
+func NewFeatherWand(cfg FeatherWandConfig) *FeatherWand {
+	if cfg.InitialDelayMax <= 0 {
+		cfg.InitialDelayMax = 50
+	}
+	if cfg.Length <= 0 {
+		cfg.Length = 12
+	}
+	if cfg.Stiffness <= 0 || cfg.Stiffness > 1 {
+		cfg.Stiffness = 0.9
+	}
+	if cfg.Damping < 0 || cfg.Damping >= 1 {
+		cfg.Damping = 0.02
+	}
+	return &FeatherWand{
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		Color:     cfg.Color,
+		segments:  cfg.Length,
+		stiffness: cfg.Stiffness,
+		damping:   cfg.Damping,
+		intensity: 1.0,
+		log:       discardLogger,
+	}
+}
@@ -0,0 +1,47 @@
+package kitty
+
+import "testing"
+
+func TestDragGrabsOneWand(t *testing.T) {
+	k := newTestKitty(t, KittyConfig{SnakeCount: 1})
+	a := NewFeatherWand(DefaultFeatherWandConfig())
+	b := NewFeatherWand(DefaultFeatherWandConfig())
+	k.objects = []KittyPlayThing{a, b}
+	for _, f := range []*FeatherWand{a, b} {
+		f.Spawn(k.world)
+		f.anchorX = 20
+	}
+	// let the strings drop in and hang still
+	for i := 0; i < 60; i++ {
+		k.step()
+	}
+
+	// a drag that starts away from both strings moves neither
+	k.pointer.move(Point{X: 60, Y: 2}, true)
+	k.step()
+	k.pointer.move(Point{X: 65, Y: 5}, true)
+	k.step()
+	if a.dragged || b.dragged {
+		t.Fatal("a drag away from the wands picked one up")
+	}
+	k.pointer.move(Point{X: 65, Y: 5}, false)
+	k.step()
+
+	// one that starts on the strings picks up only the first wand
+	tip := a.points()[len(a.points())-1]
+	k.pointer.move(tip, true)
+	k.step()
+	k.pointer.move(Point{X: 50, Y: 8}, true)
+	k.step()
+	if !a.dragged || b.dragged {
+		t.Fatalf("dragged = %v, %v, want only the first wand", a.dragged, b.dragged)
+	}
+	if a.anchorX != 50 || a.anchorY != 8 {
+		t.Errorf("grabbed wand anchored at %v,%v, want the drag at 50,8", a.anchorX, a.anchorY)
+	}
+	k.pointer.move(Point{X: 50, Y: 8}, false)
+	k.step()
+	if a.dragged || k.world.Grabbed != nil {
+		t.Error("letting go of the button did not drop the wand")
+	}
+}
@@ -0,0 +1,221 @@
+package kitty
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Firefly drifts slowly on the air and blinks: it stays dark for a while,
+// then its glow swells and fades again. A firefly caught in a web blinks
+// fast.
+type Firefly struct {
+	Lifecycle
+	prey
+	Color tcell.Color
+
+	x          float64
+	y          float64
+	vx         float64
+	vy         float64
+	glow       float64
+	blinkPhase float64
+	darkTicks  int
+	lifeTicks  int
+	intensity  float64
+	sprite     *Sprite
+}
+
+// fireflyRamp goes from dark to fully lit.
+var fireflyRamp = []tcell.Color{color.DarkOliveGreen, color.Olive, color.YellowGreen, color.Yellow, color.LightYellow}
+
+func (f *Firefly) Name() string {
+	return "firefly"
+}
+
+func (f *Firefly) Layer() Layer {
+	return LayerCritters
+}
+
+func (f *Firefly) Update(w *World) {
+	f.world = w
+	width, height := w.Width, w.Height
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if !f.Tick(f, w) {
+		return
+	}
+	f.blink()
+	if f.held(f) {
+		return
+	}
+	if f.lifeTicks > 0 {
+		f.lifeTicks--
+	}
+	leaving := f.Leaving() || f.lifeTicks == 0
+
+	// drift on the air, bobbing gently
+	f.vx = clampFloat(f.vx+f.world.Range(-0.05, 0.05), -0.4, 0.4)
+	f.vy = clampFloat(f.vy+f.world.Range(-0.03, 0.03), -0.2, 0.2)
+	if leaving {
+		// float up and away
+		f.vy = math.Min(f.vy, -0.15)
+	} else {
+		if f.x < 2 || f.x > float64(width-3) {
+			f.vx += math.Copysign(0.05, float64(width)/2-f.x)
+		}
+		if f.y < 1 || f.y > float64(height-2) {
+			f.vy += math.Copysign(0.03, float64(height)/2-f.y)
+		}
+	}
+	act := activity(f.intensity)
+	f.x += f.vx * act
+	f.y += f.vy*act + math.Sin(f.blinkPhase*0.5)*0.05
+
+	if leaving && (f.x < -1 || f.x > float64(width) || f.y < -1 || f.y > float64(height)) {
+		f.Despawn(DespawnGone)
+	}
+}
+
+// blink runs the glow: dark, then a swell and fade over about a second.
+func (f *Firefly) blink() {
+	if f.darkTicks > 0 {
+		f.darkTicks--
+		f.glow = 0
+		return
+	}
+	step := 0.25
+	if f.stuck {
+		step = 0.8
+	}
+	f.blinkPhase += step
+	f.glow = math.Max(0, math.Sin(f.blinkPhase))
+	if f.blinkPhase >= math.Pi {
+		f.blinkPhase = 0
+		f.glow = 0
+		if !f.stuck {
+			f.darkTicks = calmWait(10+f.world.Intn(40), f.intensity)
+		}
+	}
+}
+
+func (f *Firefly) Spawn(w *World) {
+	f.world = w
+	f.Start()
+	f.sprite = w.Sprites.Pick("firefly", w)
+	f.x = f.world.Range(2, math.Max(2, float64(w.Width-3)))
+	f.y = float64(w.Height)
+	f.vx = f.world.Range(-0.2, 0.2)
+	f.vy = -0.2
+	f.glow = 0
+	f.blinkPhase = 0
+	f.darkTicks = f.world.Intn(20)
+	f.lifeTicks = calmWait(400+f.world.Intn(400), f.intensity)
+	f.stuck = false
+	f.stuckTicks = 0
+}
+
+// Despawn takes the firefly off screen.
+func (f *Firefly) Despawn(reason DespawnReason) {
+	f.despawned(f, reason)
+	wait := 0
+	switch reason {
+	case DespawnGone:
+		wait = calmWait(100+f.world.Intn(200), f.intensity)
+	case DespawnHit:
+		wait = 60 + f.world.Intn(120)
+	case DespawnEaten:
+		wait = calmWait(120+f.world.Intn(200), f.intensity)
+	}
+	f.Stop(wait)
+}
+
+func (f *Firefly) HitPoint(width, height int) (int, int, bool) {
+	if !f.Active() {
+		return 0, 0, false
+	}
+	return cellAt(f.x, f.y, width, height)
+}
+
+func (f *Firefly) Bounds() Rect {
+	x, y, ok := f.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return Rect{}
+	}
+	return rectAround(x, y, 1, 0)
+}
+
+func (f *Firefly) Draw(c *Canvas) {
+	width, height := c.Size()
+	x, y, ok := f.HitPoint(width, height)
+	if !ok {
+		return
+	}
+	ramp := fireflyRamp
+	if f.Color != tcell.ColorDefault && f.Color != 0 {
+		ramp = []tcell.Color{color.DarkOliveGreen, f.Color}
+	}
+	fg := ramp[min(int(f.glow*float64(len(ramp))), len(ramp)-1)]
+	frame := "dark"
+	switch {
+	case f.glow > 0.7:
+		frame = "bright"
+	case f.glow > 0.3:
+		frame = "lit"
+	}
+	// the glow spills onto the cells beside it in a dimmer color
+	f.sprite.Frame(frame).Draw(c, x, y, fg, ramp[len(ramp)/2])
+}
+
+func (f *Firefly) StickToWeb() bool {
+	f.stick(f.x, f.y, 80+f.world.Intn(100))
+	f.darkTicks = 0
+	return true
+}
+
+// BreakFree releases a firefly from a web.
+func (f *Firefly) BreakFree() {
+	if f.free(f.x, f.y) {
+		f.vy = -0.2
+	}
+}
+
+func (f *Firefly) Struggle() float64 {
+	return 0.01
+}
+
+func (f *Firefly) SetIntensity(intensity float64) {
+	f.intensity = intensity
+}
+
+func (f *Firefly) SetLogger(log *slog.Logger) {
+	f.log = log
+}
+
+func (f *Firefly) DebugInfo() (int, int, string, bool) {
+	x, y, ok := f.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return 0, 0, "", false
+	}
+	state := "drift"
+	if f.stuck {
+		state = fmt.Sprintf("stuck(%d)", f.stuckTicks)
+	}
+	return x, y, fmt.Sprintf("firefly %s glow=%.1f", state, f.glow), true
+}
+
+func NewFirefly(cfg FireflyConfig) *Firefly {
+	if cfg.InitialDelayMax <= 0 {
+		cfg.InitialDelayMax = 80
+	}
+	return &Firefly{
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		prey:      prey{name: "firefly", log: discardLogger},
+		Color:     cfg.Color,
+		intensity: 1.0,
+	}
+}
@@ -0,0 +1,353 @@
+package kitty
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// FishSchool is a school of little fish, "><>" and "<><", flocking with the
+// boids rules: each fish keeps clear of its neighbours, matches their heading
+// and drifts towards the middle of the school. The school scatters from the
+// laser dot and from pounces, and bubbles rise from the bottom while it is
+// on screen.
+type FishSchool struct {
+	Lifecycle
+	Color tcell.Color
+
+	fish       []fish
+	size       int
+	maxSpeed   float64
+	goalX      float64
+	goalY      float64
+	lifeTicks  int
+	bubbleX    float64
+	bubbleWait int
+	intensity  float64
+	log        *slog.Logger
+	sprite     *Sprite
+	world      *World
+}
+
+type fish struct {
+	x, y    float64
+	vx, vy  float64
+	scatter int
+	color   tcell.Color
+}
+
+// Boids distances are in rows; columns count half as much.
+const (
+	fishSeparation = 1.5
+	fishNeighbour  = 6.0
+	fishThreat     = 7.0
+)
+
+var fishColors = []tcell.Color{color.Orange, color.Gold, color.Aqua, color.Fuchsia, color.Lime}
+
+func (f *FishSchool) Name() string {
+	return "fish"
+}
+
+func (f *FishSchool) Layer() Layer {
+	return LayerCritters
+}
+
+func (f *FishSchool) Update(w *World) {
+	f.world = w
+	width, height := w.Width, w.Height
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if !f.Tick(f, w) {
+		return
+	}
+
+	leaving := f.Leaving() || f.lifeTicks <= 0
+	if f.lifeTicks > 0 {
+		f.lifeTicks--
+	}
+	if !leaving && (f.world.Float64() < 0.01 || f.nearGoal()) {
+		f.pickGoal(width, height)
+	}
+	threats := append(append([]Point(nil), w.Lasers...), w.Pounces...)
+	act := activity(f.intensity)
+	calm := f.scattered() == 0
+
+	for i := range f.fish {
+		fi := &f.fish[i]
+		ax, ay := f.steer(i, threats, width, height, leaving)
+		fi.vx += ax
+		fi.vy += ay
+		// fish swim mostly level
+		fi.vy *= 0.9
+		maxSpeed := f.maxSpeed
+		if fi.scatter > 0 {
+			fi.scatter--
+			maxSpeed *= 2.5
+		}
+		speed := math.Hypot(fi.vx/cellAspect, fi.vy)
+		minSpeed := 0.3 * f.maxSpeed / cellAspect
+		if speed > maxSpeed/cellAspect {
+			s := maxSpeed / cellAspect / speed
+			fi.vx *= s
+			fi.vy *= s
+		} else if speed < minSpeed && speed > 0 {
+			s := minSpeed / speed
+			fi.vx *= s
+			fi.vy *= s
+		}
+		fi.x += fi.vx * act
+		fi.y += fi.vy * act
+		if !leaving {
+			fi.y = clampFloat(fi.y, 0, float64(height-1))
+		}
+	}
+
+	if n := f.scattered(); calm && n > 0 {
+		f.log.Debug("fish scattered", "x", math.Round(f.centerX()), "count", n)
+	}
+	f.bubble(height)
+
+	if leaving && f.offScreen(width, height) {
+		f.Despawn(DespawnGone)
+	}
+}
+
+// steer sums the boids rules for fish i, plus fleeing threats and keeping
+// to the screen, into one acceleration.
+func (f *FishSchool) steer(i int, threats []Point, width, height int, leaving bool) (float64, float64) {
+	fi := &f.fish[i]
+	var sepX, sepY, alignX, alignY, cohX, cohY float64
+	neighbours := 0
+	for j := range f.fish {
+		if j == i {
+			continue
+		}
+		fj := &f.fish[j]
+		dx := (fj.x - fi.x) / cellAspect
+		dy := fj.y - fi.y
+		d := math.Hypot(dx, dy)
+		if d > fishNeighbour {
+			continue
+		}
+		neighbours++
+		alignX += fj.vx
+		alignY += fj.vy
+		cohX += dx
+		cohY += dy
+		if d < fishSeparation {
+			sepX -= dx / math.Max(d*d, 0.1)
+			sepY -= dy / math.Max(d*d, 0.1)
+		}
+	}
+	var ax, ay float64
+	if neighbours > 0 {
+		n := float64(neighbours)
+		ax += (alignX/n - fi.vx) * 0.05
+		ay += (alignY/n - fi.vy) * 0.05
+		ax += cohX / n * cellAspect * 0.01
+		ay += cohY / n * 0.01
+	}
+	ax += sepX * cellAspect * 0.08
+	ay += sepY * 0.08
+
+	for _, t := range threats {
+		dx := (fi.x - float64(t.X)) / cellAspect
+		dy := fi.y - float64(t.Y)
+		d := math.Hypot(dx, dy)
+		if d > fishThreat {
+			continue
+		}
+		push := (fishThreat - d) / fishThreat
+		d = math.Max(d, 0.5)
+		ax += dx / d * push * cellAspect * 0.6
+		ay += dy / d * push * 0.6
+		if fi.scatter == 0 {
+			fi.scatter = 10 + f.world.Intn(10)
+		}
+	}
+
+	if leaving {
+		// head out whichever side is closer
+		dir := 1.0
+		if f.centerX() < float64(width)/2 {
+			dir = -1
+		}
+		ax += dir * 0.08
+		return ax, ay
+	}
+
+	ax += clampFloat((f.goalX-fi.x)/cellAspect, -1, 1) * 0.02 * cellAspect
+	ay += clampFloat(f.goalY-fi.y, -1, 1) * 0.02
+	if fi.x < 2 {
+		ax += 0.1
+	} else if fi.x > float64(width-3) {
+		ax -= 0.1
+	}
+	if fi.y < 1 {
+		ay += 0.05
+	} else if fi.y > float64(height-2) {
+		ay -= 0.05
+	}
+	return ax, ay
+}
+
+// bubble lets a column of bubbles rise from the bottom every few ticks.
+func (f *FishSchool) bubble(height int) {
+	f.bubbleWait--
+	if f.bubbleWait > 0 {
+		return
+	}
+	f.bubbleWait = 3 + f.world.Intn(6)
+	f.world.Effects.Emit(fxBubble, f.bubbleX+f.world.Range(-0.5, 0.5), float64(height-1))
+}
+
+func (f *FishSchool) centerX() float64 {
+	if len(f.fish) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, fi := range f.fish {
+		sum += fi.x
+	}
+	return sum / float64(len(f.fish))
+}
+
+// scattered counts the fish still fleeing something.
+func (f *FishSchool) scattered() int {
+	n := 0
+	for _, fi := range f.fish {
+		if fi.scatter > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *FishSchool) nearGoal() bool {
+	for _, fi := range f.fish {
+		if math.Hypot((fi.x-f.goalX)/cellAspect, fi.y-f.goalY) < 3 {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FishSchool) pickGoal(width, height int) {
+	f.goalX = f.world.Range(4, math.Max(4, float64(width-4)))
+	f.goalY = f.world.Range(2, math.Max(2, float64(height-3)))
+}
+
+func (f *FishSchool) offScreen(width, height int) bool {
+	for _, fi := range f.fish {
+		if fi.x > -3 && fi.x < float64(width+3) && fi.y > -1 && fi.y < float64(height+1) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *FishSchool) Spawn(w *World) {
+	f.world = w
+	f.Start()
+	f.sprite = w.Sprites.Pick("fish", w)
+	width, height := w.Width, w.Height
+	dir := 1.0
+	startX := -3.0
+	if f.world.Intn(2) == 0 {
+		dir = -1
+		startX = float64(width + 2)
+	}
+	startY := f.world.Range(2, math.Max(2, float64(height-3)))
+	schoolColor := f.Color
+	if schoolColor == tcell.ColorDefault || schoolColor == 0 {
+		schoolColor = fishColors[f.world.Intn(len(fishColors))]
+	}
+	f.fish = f.fish[:0]
+	for i := 0; i < f.size; i++ {
+		f.fish = append(f.fish, fish{
+			x:     startX - dir*f.world.Range(0, 8),
+			y:     startY + f.world.Range(-2, 2),
+			vx:    dir * f.maxSpeed * f.world.Range(0.5, 1),
+			vy:    f.world.Range(-0.1, 0.1),
+			color: schoolColor,
+		})
+	}
+	f.pickGoal(width, height)
+	f.lifeTicks = calmWait(400+f.world.Intn(400), f.intensity)
+	f.bubbleX = f.world.Range(2, math.Max(2, float64(width-3)))
+	f.bubbleWait = 0
+}
+
+func (f *FishSchool) Despawn(reason DespawnReason) {
+	wait := 0
+	if reason != DespawnRemoved {
+		wait = calmWait(100+f.world.Intn(200), f.intensity)
+	}
+	f.fish = f.fish[:0]
+	f.Stop(wait)
+}
+
+func (f *FishSchool) Bounds() Rect {
+	var r Rect
+	for _, fi := range f.fish {
+		r = r.Union(rectAround(int(math.Round(fi.x)), int(math.Round(fi.y)), 2, 0))
+	}
+	return r
+}
+
+func (f *FishSchool) Draw(c *Canvas) {
+	for _, fi := range f.fish {
+		x := int(math.Round(fi.x))
+		y := int(math.Round(fi.y))
+		frame := f.sprite.Frame("swim")
+		if fi.vx < 0 {
+			frame = frame.Flip()
+		}
+		frame.Draw(c, x, y, fi.color, fi.color)
+	}
+}
+
+func (f *FishSchool) SetIntensity(intensity float64) {
+	f.intensity = intensity
+}
+
+func (f *FishSchool) SetLogger(log *slog.Logger) {
+	f.log = log
+}
+
+func (f *FishSchool) DebugInfo() (int, int, string, bool) {
+	if !f.Active() || len(f.fish) == 0 {
+		return 0, 0, "", false
+	}
+	sumY := 0.0
+	for _, fi := range f.fish {
+		sumY += fi.y
+	}
+	info := fmt.Sprintf("fish %d scatter=%d goal=(%.0f,%.0f)", len(f.fish), f.scattered(), f.goalX, f.goalY)
+	return int(math.Round(f.centerX())), int(math.Round(sumY / float64(len(f.fish)))), info, true
+}
+
+func NewFishSchool(cfg FishConfig) *FishSchool {
+	if cfg.InitialDelayMax <= 0 {
+		cfg.InitialDelayMax = 60
+	}
+	if cfg.Size <= 0 {
+		cfg.Size = 6
+	}
+	if cfg.Speed <= 0 {
+		cfg.Speed = 0.8
+	}
+	return &FishSchool{
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		Color:     cfg.Color,
+		size:      cfg.Size,
+		maxSpeed:  cfg.Speed,
+		intensity: 1.0,
+		log:       discardLogger,
+	}
+}
@@ -0,0 +1,250 @@
+package kitty
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Fly zips around in tight, random loops. It also moves towards and away
+// from the screen, looking bigger and faster up close. Now and then it lands
+// and rubs its legs together before taking off again.
+type Fly struct {
+	Lifecycle
+	prey
+	Color tcell.Color
+
+	x         float64
+	y         float64
+	z         float64
+	vz        float64
+	heading   float64
+	turn      float64
+	speed     float64
+	landTicks int
+	lifeTicks int
+	rubPhase  int
+	intensity float64
+	sprite    *Sprite
+}
+
+func (f *Fly) Name() string {
+	return "fly"
+}
+
+func (f *Fly) Layer() Layer {
+	return LayerCritters
+}
+
+func (f *Fly) Update(w *World) {
+	f.world = w
+	width, height := w.Width, w.Height
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if !f.Tick(f, w) {
+		return
+	}
+	f.rubPhase++
+	if f.held(f) {
+		return
+	}
+	if f.lifeTicks > 0 {
+		f.lifeTicks--
+	}
+	leaving := f.Leaving() || f.lifeTicks == 0
+
+	if f.landTicks > 0 {
+		f.landTicks--
+		if f.landTicks > 0 && !leaving {
+			return
+		}
+		f.log.Debug("fly took off", "x", math.Round(f.x), "y", math.Round(f.y))
+		f.landTicks = 0
+	}
+
+	// tight loops: the turn rate wanders and sometimes flips
+	f.turn = clampFloat(f.turn+f.world.Range(-0.15, 0.15), -0.7, 0.7)
+	if f.world.Float64() < 0.05 {
+		f.turn = -f.turn
+	}
+	f.heading += f.turn
+	f.vz = clampFloat(f.vz+f.world.Range(-0.02, 0.02), -0.05, 0.05)
+	f.z += f.vz
+	if f.z < 0 || f.z > 1 {
+		f.vz = -f.vz
+		f.z = clampFloat(f.z, 0, 1)
+	}
+
+	if !leaving {
+		// stay on screen by turning back towards the middle near an edge
+		if f.x < 2 || f.x > float64(width-3) || f.y < 1 || f.y > float64(height-2) {
+			back := math.Atan2(float64(height)/2-f.y, (float64(width)/2-f.x)/cellAspect)
+			f.heading += angleDiff(back, f.heading) * 0.3
+		}
+		if f.world.Float64() < 0.005*activity(f.intensity) {
+			f.landTicks = 20 + f.world.Intn(40)
+			f.log.Debug("fly landed", "x", math.Round(f.x), "y", math.Round(f.y))
+			return
+		}
+	} else {
+		f.turn *= 0.5
+	}
+
+	speed := f.speed * (0.6 + 0.8*f.z) * activity(f.intensity)
+	f.x += math.Cos(f.heading) * speed * cellAspect
+	f.y += math.Sin(f.heading) * speed
+
+	if leaving && (f.x < -2 || f.x > float64(width+1) || f.y < -2 || f.y > float64(height+1)) {
+		f.Despawn(DespawnGone)
+	}
+}
+
+// angleDiff is the signed smallest turn from b to a.
+func angleDiff(a, b float64) float64 {
+	d := math.Mod(a-b+math.Pi, 2*math.Pi)
+	if d < 0 {
+		d += 2 * math.Pi
+	}
+	return d - math.Pi
+}
+
+func (f *Fly) Spawn(w *World) {
+	f.world = w
+	f.Start()
+	f.sprite = w.Sprites.Pick("fly", w)
+	f.x, f.y = randomEdgePoint(w, w.Width, w.Height)
+	f.heading = math.Atan2(float64(w.Height)/2-f.y, (float64(w.Width)/2-f.x)/cellAspect)
+	f.turn = f.world.Range(-0.4, 0.4)
+	f.z = f.world.Float64()
+	f.vz = 0
+	f.speed = f.world.Range(0.7, 1.1)
+	f.landTicks = 0
+	f.lifeTicks = calmWait(300+f.world.Intn(400), f.intensity)
+	f.stuck = false
+	f.stuckTicks = 0
+}
+
+// Despawn takes the fly off screen.
+func (f *Fly) Despawn(reason DespawnReason) {
+	f.despawned(f, reason)
+	wait := 0
+	switch reason {
+	case DespawnGone:
+		wait = calmWait(80+f.world.Intn(160), f.intensity)
+	case DespawnHit:
+		wait = 60 + f.world.Intn(120)
+	case DespawnEaten:
+		wait = calmWait(100+f.world.Intn(160), f.intensity)
+	}
+	f.Stop(wait)
+}
+
+func (f *Fly) HitPoint(width, height int) (int, int, bool) {
+	if !f.Active() {
+		return 0, 0, false
+	}
+	return cellAt(f.x, f.y, width, height)
+}
+
+func (f *Fly) Bounds() Rect {
+	x, y, ok := f.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return Rect{}
+	}
+	return rectAround(x, y, 1, 0)
+}
+
+func (f *Fly) Draw(c *Canvas) {
+	width, height := c.Size()
+	x, y, ok := f.HitPoint(width, height)
+	if !ok {
+		return
+	}
+	fg := f.Color
+	if fg == tcell.ColorDefault || fg == 0 {
+		fg = color.Gray
+	}
+	f.sprite.Frame(f.frame()).Draw(c, x, y, fg, fg)
+}
+
+// frame picks the sprite frame for what the fly is doing.
+func (f *Fly) frame() string {
+	switch {
+	case f.stuck:
+		// buzzing against the silk
+		if f.rubPhase%2 == 0 {
+			return "stuck"
+		}
+		return "buzzing"
+	case f.landTicks > 0:
+		// rubbing its legs together
+		if f.rubPhase/3%2 == 0 {
+			return "rub"
+		}
+		return "rubbing"
+	case f.z > 0.66:
+		return "near"
+	case f.z > 0.33:
+		return "mid"
+	}
+	return "far"
+}
+
+func (f *Fly) StickToWeb() bool {
+	if f.landTicks > 0 {
+		return false
+	}
+	f.stick(f.x, f.y, 60+f.world.Intn(100))
+	return true
+}
+
+// BreakFree releases a fly from a web.
+func (f *Fly) BreakFree() {
+	if f.free(f.x, f.y) {
+		f.heading += math.Pi
+	}
+}
+
+// Struggle is weak; a fly barely tears the web.
+func (f *Fly) Struggle() float64 {
+	return 0.01
+}
+
+func (f *Fly) SetIntensity(intensity float64) {
+	f.intensity = intensity
+}
+
+func (f *Fly) SetLogger(log *slog.Logger) {
+	f.log = log
+}
+
+func (f *Fly) DebugInfo() (int, int, string, bool) {
+	x, y, ok := f.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return 0, 0, "", false
+	}
+	state := "zip"
+	switch {
+	case f.stuck:
+		state = fmt.Sprintf("stuck(%d)", f.stuckTicks)
+	case f.landTicks > 0:
+		state = "landed"
+	}
+	return x, y, fmt.Sprintf("fly %s z=%.1f turn=%.2f", state, f.z, f.turn), true
+}
+
+func NewFly(cfg FlyConfig) *Fly {
+	if cfg.InitialDelayMax <= 0 {
+		cfg.InitialDelayMax = 60
+	}
+	return &Fly{
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		prey:      prey{name: "fly", log: discardLogger},
+		Color:     cfg.Color,
+		intensity: 1.0,
+	}
+}
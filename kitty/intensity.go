@@ -0,0 +1,102 @@
+package kitty
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// IntensityCurve shapes a play period like a real hunt: a slow stalking
+// warmup, a frantic peak and a cooldown while the "prey" is eaten. The curve
+// repeats for as long as the play period lasts.
+type IntensityCurve struct {
+	Warmup   time.Duration
+	Peak     time.Duration
+	Cooldown time.Duration
+}
+
+const (
+	intensityStalk = 0.2
+	intensityRest  = 0.1
+)
+
+// ParseIntensityCurve parses "warmup,peak,cooldown", e.g. "2m,6m,2m".
+func ParseIntensityCurve(spec string) (IntensityCurve, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return IntensityCurve{}, nil
+	}
+	parts := strings.Split(spec, ",")
+	if len(parts) != 3 {
+		return IntensityCurve{}, fmt.Errorf("intensity curve %q: expected warmup,peak,cooldown", spec)
+	}
+	var d [3]time.Duration
+	for i, part := range parts {
+		v, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return IntensityCurve{}, fmt.Errorf("intensity curve %q: %w", spec, err)
+		}
+		if v < 0 {
+			return IntensityCurve{}, fmt.Errorf("intensity curve %q: durations must not be negative", spec)
+		}
+		d[i] = v
+	}
+	return IntensityCurve{Warmup: d[0], Peak: d[1], Cooldown: d[2]}, nil
+}
+
+func (c IntensityCurve) IsZero() bool {
+	return c.Warmup+c.Peak+c.Cooldown <= 0
+}
+
+// At returns the intensity (0-1) at the given time into a play period.
+func (c IntensityCurve) At(elapsed time.Duration) float64 {
+	total := c.Warmup + c.Peak + c.Cooldown
+	if total <= 0 {
+		return 1.0
+	}
+	t := elapsed % total
+	if t < c.Warmup {
+		u := smoothStep(float64(t) / float64(c.Warmup))
+		return intensityStalk + (1-intensityStalk)*u
+	}
+	t -= c.Warmup
+	if t < c.Peak {
+		return 1.0
+	}
+	t -= c.Peak
+	u := smoothStep(float64(t) / float64(c.Cooldown))
+	return 1 - (1-intensityRest)*u
+}
+
+// validateIntensity reports whether v is an intensity Kitty can play at. 0
+// stands for the default, full intensity.
+func validateIntensity(v float64) error {
+	if v < 0 || v > 1 {
+		return fmt.Errorf("intensity %v is outside 0-1", v)
+	}
+	return nil
+}
+
+// KittyIntensityAware is implemented by playthings that scale their behaviour
+// with the current play intensity.
+type KittyIntensityAware interface {
+	SetIntensity(intensity float64)
+}
+
+// activity maps intensity onto a multiplier for behaviour that was tuned at
+// full intensity. Even a resting plaything keeps a little life in it.
+func activity(intensity float64) float64 {
+	return 0.25 + 0.75*clampFloat(intensity, 0, 1)
+}
+
+// calmWait stretches a respawn wait so critters show up less often when the
+// intensity is low.
+func calmWait(ticks int, intensity float64) int {
+	return int(math.Round(float64(ticks) / activity(intensity)))
+}
+
+func smoothStep(u float64) float64 {
+	u = clampFloat(u, 0, 1)
+	return u * u * (3 - 2*u)
+}
@@ -2,7 +2,10 @@ package kitty
 
 import (
 	"context"
+	"log/slog"
 	"math"
+	"reflect"
+	"sync/atomic"
 	"time"
 
 	"github.com/gdamore/tcell/v3"
@@ -27,8 +30,41 @@ type Kitty struct {
 	s            tcell.Screen
 	objects      []KittyPlayThing
 	config       KittyConfig
+
+	// Intensity (0-1) scales how lively every plaything is. It follows the
+	// configured IntensityCurve when one is set.
+	Intensity float64
+
+	session       *sessionClock
+	phase         SessionPhase
+	windingDown   bool
+	windDownStart time.Time
+	phaseStart    time.Time
+
+	catchRequested atomic.Bool
+	nextCatch      time.Time
+
+	stats     *sessionStats
+	mouseDown bool
+	pointer   pointerState
+	metrics   *metrics
+	world     *World
+	effects   *Effects
+
+	log *slog.Logger
+	fps fpsCounter
+
+	canvas Canvas
+	// scripts each add a critter to every play window.
+	scripts []*Script
+	// extensions are the registered plaything types and their options.
+	extensions []extension
+	layers layerOrder
 }
 
+// windDownLimit caps how long playthings get to exit before they are removed.
+const windDownLimit = 10 * time.Second
+
 func (k *Kitty) EventLoop(ctx context.Context, cancel context.CancelFunc) {
 	for {
 		select {
@@ -44,6 +80,23 @@ func (k *Kitty) EventLoop(ctx context.Context, cancel context.CancelFunc) {
 				cancel()
 				return
 			}
+			k.metrics.input("key")
+			if ev.Key() == tcell.KeyRune && ev.Str() == "c" {
+				k.catchRequested.Store(true)
+				break
+			}
+			k.stats.pounces.Add(1)
+		case *tcell.EventMouse:
+			k.metrics.input("mouse")
+			// count presses, not the release or drag that follows
+			down := ev.Buttons()&(tcell.Button1|tcell.Button2|tcell.Button3) != 0
+			x, y := ev.Position()
+			if down && !k.mouseDown {
+				k.stats.pounces.Add(1)
+				k.pointer.press(Point{X: x, Y: y})
+			}
+			k.pointer.move(Point{X: x, Y: y}, down)
+			k.mouseDown = down
 		case *tcell.EventInterrupt:
 			return
 		}
@@ -51,8 +104,173 @@ func (k *Kitty) EventLoop(ctx context.Context, cancel context.CancelFunc) {
 }
 
 func (k *Kitty) Play(ctx context.Context) {
-	//k.objects = append(k.objects, &BouncySquare{X1: 0, Len: 2, Vx: 1, Vy: 1})
+	k.session.start = time.Now()
+	// the event loop may already be counting pounces, so keep the stats and
+	// only move their start
+	k.stats.stats.Start = k.session.start
+	k.phase = k.session.phase(k.session.start)
+	k.spawnForPhase(k.phase)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			now := time.Now()
+			if k.updateSession(now) {
+				return
+			}
+			k.updateIntensity(now)
+			k.updateCatch(now)
+			k.s.Clear()
+			k.step()
+			drawStart := time.Now()
+			k.metrics.observeFrame("update", drawStart.Sub(now))
+			k.draw()
+			k.s.Show()
+			k.metrics.observeFrame("draw", time.Since(drawStart))
+			k.metrics.setObjects(k.objects)
+			k.metrics.observeTick(time.Since(now))
+			k.fps.tick(now)
+			time.Sleep(frameBudget)
+		}
+
+	}
+}
+
+// step moves every plaything one tick and settles what they did to each
+// other.
+func (k *Kitty) step() {
+	k.refreshWorld()
+	for _, o := range k.objects {
+		o.Update(k.world)
+	}
+	k.handleLaserHits()
+	k.handleSnakeMeals()
+	k.handleBirdMeals()
+	k.handleWebCollisions()
+	k.handleWebDamage()
+	k.effects.Update()
+}
+
+// draw renders every plaything into the canvas layer by layer and copies
+// the result to the screen.
+func (k *Kitty) draw() {
+	width, height := k.s.Size()
+	k.canvas.reset(width, height)
+	k.canvas.SetLayer(LayerBackground)
+	k.drawGround(&k.canvas)
+	for _, o := range k.layers.sorted(k.objects) {
+		k.canvas.SetLayer(k.layers.layer(o))
+		o.Draw(&k.canvas)
+	}
+	k.canvas.SetLayer(LayerEffects)
+	k.effects.Draw(&k.canvas)
+	if k.config.Debug {
+		k.canvas.SetLayer(LayerHUD)
+		k.drawDebugOverlay()
+	}
+	k.canvas.flush(k.s)
+}
+
+// updateSession moves between play and rest when the schedule says so. The
+// current playthings are asked to leave first and the next set only spawns
+// once they are gone. It returns true when the session is over.
+func (k *Kitty) updateSession(now time.Time) bool {
+	target := k.session.phase(now)
+	if !k.windingDown {
+		if target == k.phase {
+			return false
+		}
+		k.windingDown = true
+		k.windDownStart = now
+		k.log.Info("winding down", "next", target.String())
+		for _, o := range k.objects {
+			if l, ok := o.(KittyLeaver); ok {
+				l.Leave()
+			}
+		}
+	}
+	if !k.allLeft() && now.Sub(k.windDownStart) < windDownLimit {
+		return false
+	}
+	k.windingDown = false
+	k.log.Info("session phase", "from", k.phase.String(), "to", target.String())
+	k.phase = target
+	if target == SessionOver {
+		return true
+	}
+	k.spawnForPhase(target)
+	return false
+}
+
+func (k *Kitty) updateIntensity(now time.Time) {
+	if !k.config.IntensityCurve.IsZero() {
+		k.Intensity = k.config.IntensityCurve.At(now.Sub(k.phaseStart))
+	}
+	for _, o := range k.objects {
+		if a, ok := o.(KittyIntensityAware); ok {
+			a.SetIntensity(k.Intensity)
+		}
+	}
+}
+
+// updateCatch lands the lasers when the catch key was pressed or the
+// configured catch timer runs out.
+func (k *Kitty) updateCatch(now time.Time) {
+	catch := k.catchRequested.Swap(false)
+	if !k.nextCatch.IsZero() && !now.Before(k.nextCatch) {
+		catch = true
+		k.nextCatch = now.Add(k.config.LaserConfig.CatchAfter)
+	}
+	if !catch {
+		return
+	}
+	k.log.Info("laser catch requested")
+	for _, o := range k.objects {
+		if l, ok := o.(*LaserPointer); ok {
+			l.Catch()
+		}
+	}
+}
+
+func (k *Kitty) allLeft() bool {
+	for _, o := range k.objects {
+		if l, ok := o.(KittyLeaver); ok && !l.HasLeft() {
+			return false
+		}
+	}
+	return true
+}
+
+func (k *Kitty) spawnForPhase(phase SessionPhase) {
+	for _, o := range k.objects {
+		if o.Active() {
+			o.Despawn(DespawnRemoved)
+		}
+	}
+	k.stats.retire(k.objects)
 	k.objects = k.objects[:0]
+	k.phaseStart = time.Now()
+	k.nextCatch = time.Time{}
+	if phase == SessionPlay && k.config.LaserConfig.CatchAfter > 0 {
+		k.nextCatch = k.phaseStart.Add(k.config.LaserConfig.CatchAfter)
+	}
+	switch phase {
+	case SessionPlay:
+		k.spawnPlayThings()
+	case SessionRest:
+		k.spawnRestThings()
+	}
+	for _, o := range k.objects {
+		if l, ok := o.(KittyLoggable); ok {
+			l.SetLogger(k.log)
+		}
+	}
+}
+
+func (k *Kitty) spawnPlayThings() {
+	//k.objects = append(k.objects, &BouncySquare{X1: 0, Len: 2, Vx: 1, Vy: 1})
 	cfg := k.config
 	if cfg.SnakeCount < 0 {
 		cfg.SnakeCount = 0
@@ -63,6 +281,27 @@ func (k *Kitty) Play(ctx context.Context) {
 	if cfg.ButterflyCount < 0 {
 		cfg.ButterflyCount = 0
 	}
+	if cfg.MouseCount < 0 {
+		cfg.MouseCount = 0
+	}
+	if cfg.FishCount < 0 {
+		cfg.FishCount = 0
+	}
+	if cfg.WandCount < 0 {
+		cfg.WandCount = 0
+	}
+	if cfg.YarnCount < 0 {
+		cfg.YarnCount = 0
+	}
+	if cfg.FlyCount < 0 {
+		cfg.FlyCount = 0
+	}
+	if cfg.FireflyCount < 0 {
+		cfg.FireflyCount = 0
+	}
+	if cfg.BirdCount < 0 {
+		cfg.BirdCount = 0
+	}
 	if cfg.LaserCount < 0 {
 		cfg.LaserCount = 0
 	}
@@ -78,66 +317,186 @@ func (k *Kitty) Play(ctx context.Context) {
 	for i := 0; i < cfg.ButterflyCount; i++ {
 		k.objects = append(k.objects, NewButterfly(cfg.ButterflyConfig))
 	}
+	for i := 0; i < cfg.MouseCount; i++ {
+		k.objects = append(k.objects, NewMouse(cfg.MouseConfig))
+	}
+	for i := 0; i < cfg.FishCount; i++ {
+		k.objects = append(k.objects, NewFishSchool(cfg.FishConfig))
+	}
+	for i := 0; i < cfg.WandCount; i++ {
+		k.objects = append(k.objects, NewFeatherWand(cfg.WandConfig))
+	}
+	for i := 0; i < cfg.YarnCount; i++ {
+		k.objects = append(k.objects, NewYarnBall(cfg.YarnConfig))
+	}
+	for i := 0; i < cfg.FlyCount; i++ {
+		k.objects = append(k.objects, NewFly(cfg.FlyConfig))
+	}
+	for i := 0; i < cfg.FireflyCount; i++ {
+		k.objects = append(k.objects, NewFirefly(cfg.FireflyConfig))
+	}
+	for i := 0; i < cfg.BirdCount; i++ {
+		k.objects = append(k.objects, NewBird(cfg.BirdConfig))
+	}
 	for i := 0; i < cfg.LaserCount; i++ {
 		k.objects = append(k.objects, NewLaserPointer(cfg.LaserConfig))
 	}
 	for i := 0; i < cfg.SpiderCount; i++ {
 		k.objects = append(k.objects, NewSpider(cfg.SpiderConfig))
 	}
-
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			k.s.Clear()
-			SetSnakeAvoidPoints(k.collectWebPoints())
-			for _, o := range k.objects {
-				o.Update(k.s)
-			}
-			k.handleLaserHits()
-			k.handleWebCollisions()
-			for _, o := range k.objects {
-				o.Draw(k.s)
+	for _, script := range k.scripts {
+		k.objects = append(k.objects, script.NewCritter())
+	}
+	for _, ext := range k.extensions {
+		for i := 0; i < ext.opts.Count; i++ {
+			o, err := ext.factory(ext.opts)
+			if err != nil {
+				k.log.Error("plaything not made", "name", ext.opts.Name, "err", err)
+				break
 			}
-			k.s.Show()
-			time.Sleep(55 * time.Millisecond)
+			k.objects = append(k.objects, o)
 		}
-
 	}
 }
 
+// spawnRestThings sets up the calm scene shown between play windows: a single
+// slow, dim string.
+func (k *Kitty) spawnRestThings() {
+	cfg := k.config.SwayStringConfig
+	cfg.Color = color.DarkSlateGray
+	cfg.Speed = 0.3
+	cfg.InitialDelayMax = 20
+	k.objects = append(k.objects, NewSwayString(cfg))
+}
+
 func (k *Kitty) Start(ctx context.Context) {
 	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	defer k.s.Fini()
+	k.metrics.serve(k.log)
+	defer k.metrics.shutdown()
 	go k.EventLoop(ctx, cancel)
 	k.Play(ctx)
 }
 
+// Stats reports what happened during the session so far.
+func (k *Kitty) Stats() SessionStats {
+	return k.stats.snapshot(time.Now(), k.objects)
+}
+
 func New(config KittyConfig) (*Kitty, error) {
+	return newKitty(config, tcell.NewScreen)
+}
 
-	s, err := tcell.NewScreen()
+// newKitty is New with the screen made by newScreen, so tests can play on a
+// mock terminal.
+func newKitty(config KittyConfig, newScreen func() (tcell.Screen, error)) (*Kitty, error) {
+	session, err := newSessionClock(config.Session, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	layers, err := newLayerOrder(config.Layers)
+	if err != nil {
+		return nil, err
+	}
+	extensions, err := loadExtensions(config.Extensions)
 	if err != nil {
 		return nil, err
 	}
+	if err := ValidatePalette(config.Background.Palette); err != nil {
+		return nil, err
+	}
+	if err := ValidateWebShape(config.SpiderConfig.WebShape); err != nil {
+		return nil, err
+	}
+	if err := validateIntensity(config.Intensity); err != nil {
+		return nil, err
+	}
+	var scripts []*Script
+	for _, path := range config.Scripts {
+		script, err := LoadScript(path)
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, script)
+	}
+	sprites := NewSpriteSet()
+	if config.SpritesDir != "" {
+		sprites, err = LoadSprites(config.SpritesDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var m *metrics
+	if config.MetricsAddr != "" {
+		m = newMetrics()
+		if err := m.listen(config.MetricsAddr); err != nil {
+			return nil, err
+		}
+	}
+
+	s, err := newScreen()
+	if err != nil {
+		m.close()
+		return nil, err
+	}
 	if err := s.Init(); err != nil {
+		m.close()
 		return nil, err
 	}
 
-	s.SetStyle(DEFAULT_STYLE)
+	if config.Background.Color != tcell.ColorDefault {
+		s.SetStyle(DEFAULT_STYLE.Background(config.Background.Color))
+	} else {
+		s.SetStyle(DEFAULT_STYLE)
+	}
+	s.EnableMouse(tcell.MouseButtonEvents, tcell.MouseDragEvents)
 
 	width, height := s.Size()
 
-	if (config == KittyConfig{}) {
+	// a zero config, as from KittyConfig{}, plays with the defaults
+	if reflect.ValueOf(config).IsZero() {
 		config = DefaultKittyConfig()
 	}
+	log := config.Logger
+	if log == nil {
+		log = discardLogger
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	world := NewWorld(seed)
+	world.Sprites = sprites
+	if config.MaxParticles == 0 {
+		config.MaxParticles = defaultMaxParticles
+	}
+	if config.Intensity <= 0 {
+		config.Intensity = 1.0
+	}
+	if config.MaxParticles > 0 {
+		world.Effects = newEffects(config.MaxParticles, world)
+	}
 
 	return &Kitty{
 		screenWidth:  width,
 		screenHeight: height,
 		s:            s,
 		config:       config,
+		Intensity:    config.Intensity,
+		session:      session,
+		stats:        newSessionStats(time.Now()),
+		metrics:      m,
+		log:          log,
+		world:        world,
+		effects:      world.Effects,
+		layers:       layers,
+		scripts:      scripts,
+		extensions:   extensions,
+		canvas: Canvas{
+			background: config.Background.Color,
+			dark:       config.Background.Palette == "dark",
+		},
 	}, nil
 }
 
@@ -159,18 +518,20 @@ func (k *Kitty) handleLaserHits() {
 		return
 	}
 	for _, o := range k.objects {
-		b, ok := o.(*Butterfly)
-		if !ok {
+		t, ok := o.(KittyTarget)
+		if !ok || !t.LaserTarget() {
 			continue
 		}
-		bx, by, ok := b.HitPoint(width, height)
+		tx, ty, ok := t.HitPoint(width, height)
 		if !ok {
 			continue
 		}
 		for _, l := range lasers {
-			if absInt(l.pos.X-bx) <= 1 && absInt(l.pos.Y-by) <= 1 {
+			if absInt(l.pos.X-tx) <= 1 && absInt(l.pos.Y-ty) <= 1 {
 				l.laser.TriggerFire()
-				b.Hit(bx, by)
+				t.Despawn(DespawnHit)
+				k.stats.lasered(t.Name())
+				k.metrics.collision("laser_" + t.Name())
 				break
 			}
 		}
@@ -188,7 +549,9 @@ func (k *Kitty) handleLaserHits() {
 			for _, l := range lasers {
 				if absInt(l.pos.X-sx) <= 1 && absInt(l.pos.Y-sy) <= 1 {
 					l.laser.TriggerFire()
-					s.Hit(sx, sy)
+					s.Despawn(DespawnHit)
+					k.stats.stats.SpidersDestroyed++
+					k.metrics.collision("laser_spider")
 					break
 				}
 			}
@@ -196,10 +559,73 @@ func (k *Kitty) handleLaserHits() {
 	}
 }
 
+// handleSnakeMeals lets a snake that reaches a free flying butterfly eat it
+// now and then.
+func (k *Kitty) handleSnakeMeals() {
+	width, height := k.s.Size()
+	for _, o := range k.objects {
+		snake, ok := o.(*Snake)
+		if !ok {
+			continue
+		}
+		head, ok := snake.Head()
+		if !ok {
+			continue
+		}
+		for _, bo := range k.objects {
+			b, ok := bo.(*Butterfly)
+			if !ok || b.IsStuckInWeb() {
+				continue
+			}
+			bx, by, ok := b.HitPoint(width, height)
+			if !ok || absInt(head.X-bx) > 1 || absInt(head.Y-by) > 1 {
+				continue
+			}
+			if snake.TryEat() {
+				b.Despawn(DespawnEaten)
+				k.stats.stats.ButterfliesEatenBySnakes++
+				k.metrics.collision("snake_butterfly")
+			}
+			break
+		}
+	}
+}
+
+// handleBirdMeals lets a diving bird catch the free flying butterfly it
+// reaches.
+func (k *Kitty) handleBirdMeals() {
+	width, height := k.s.Size()
+	for _, o := range k.objects {
+		bird, ok := o.(*Bird)
+		if !ok || !bird.Diving() {
+			continue
+		}
+		x, y, ok := bird.HitPoint(width, height)
+		if !ok {
+			continue
+		}
+		for _, bo := range k.objects {
+			b, ok := bo.(*Butterfly)
+			if !ok || b.IsStuckInWeb() {
+				continue
+			}
+			bx, by, ok := b.HitPoint(width, height)
+			if !ok || absInt(x-bx) > 2 || absInt(y-by) > 1 {
+				continue
+			}
+			b.Despawn(DespawnEaten)
+			bird.Ate()
+			k.stats.stats.ButterfliesEatenByBirds++
+			k.metrics.collision("bird_butterfly")
+			break
+		}
+	}
+}
+
 func (k *Kitty) handleWebCollisions() {
 	width, height := k.s.Size()
 	
-	// Check if butterflies hit webs and notify spiders
+	// Check if prey hit webs and notify spiders
 	for _, spiderObj := range k.objects {
 		spider, ok := spiderObj.(*Spider)
 		if !ok {
@@ -208,26 +634,38 @@ func (k *Kitty) handleWebCollisions() {
 		
 		if spider.IsHunting() {
 			// Spider is already hunting, check if it's eating
-			for _, butterflyObj := range k.objects {
-				butterfly, ok := butterflyObj.(*Butterfly)
-				if !ok || !butterfly.IsStuckInWeb() {
+			preyX, preyY := spider.PreyPoint()
+			spiderX := int(math.Round(spider.x))
+			spiderY := int(math.Round(spider.y))
+			preyFound := false
+			for _, o := range k.objects {
+				prey, ok := o.(KittyPrey)
+				if !ok || !prey.IsStuckInWeb() {
 					continue
 				}
 				
-				bx, by, ok := butterfly.HitPoint(width, height)
+				px, py, ok := prey.HitPoint(width, height)
 				if !ok {
 					continue
 				}
+				if absInt(preyX-px) <= 1 && absInt(preyY-py) <= 1 {
+					preyFound = true
+				}
 				
-				spiderX := int(math.Round(spider.x))
-				spiderY := int(math.Round(spider.y))
-				
-				// If spider is close enough, eat the butterfly
-				if absInt(spiderX-bx) <= 1 && absInt(spiderY-by) <= 1 {
-					butterfly.BeEaten()
+				// If spider is close enough, eat the prey
+				if absInt(spiderX-px) <= 1 && absInt(spiderY-py) <= 1 {
+					prey.Despawn(DespawnEaten)
+					spider.EatPrey()
+					k.stats.eatenBySpider(prey.Name())
+					k.metrics.collision("spider_" + prey.Name())
+					preyFound = true
 					break
 				}
 			}
+			// the prey struggled free or was shot before the spider got there
+			if !preyFound {
+				spider.PreyLost()
+			}
 			continue
 		}
 		
@@ -236,28 +674,27 @@ func (k *Kitty) handleWebCollisions() {
 			continue
 		}
 		
-		// Check if butterflies hit this spider's web
-		for _, butterflyObj := range k.objects {
-			butterfly, ok := butterflyObj.(*Butterfly)
-			if !ok {
-				continue
-			}
-			
-			if butterfly.IsStuckInWeb() {
+		// Check if prey hit this spider's web
+		for _, o := range k.objects {
+			prey, ok := o.(KittyPrey)
+			if !ok || prey.IsStuckInWeb() {
 				continue
 			}
 			
-			bx, by, ok := butterfly.HitPoint(width, height)
+			px, py, ok := prey.HitPoint(width, height)
 			if !ok {
 				continue
 			}
 			
 			// Check collision with web points
 			for _, p := range webPoints {
-				if absInt(p.X-bx) <= 1 && absInt(p.Y-by) <= 1 {
-					butterfly.StickToWeb()
-					// Notify spider to hunt
-					spider.HuntPrey(float64(bx), float64(by))
+				if absInt(p.X-px) <= 1 && absInt(p.Y-py) <= 1 {
+					if prey.StickToWeb() {
+						k.stats.webbed(prey.Name())
+						k.metrics.collision("web_" + prey.Name())
+						// Notify spider to hunt
+						spider.HuntPrey(float64(px), float64(py))
+					}
 					break
 				}
 			}
@@ -265,16 +702,63 @@ func (k *Kitty) handleWebCollisions() {
 	}
 }
 
-func (k *Kitty) collectWebPoints() []Point {
-	var webPoints []Point
+// handleWebDamage tears webs: trapped prey struggles against the
+// strands holding them, snakes break whatever they slither through and, when
+// lasers may hit spiders, the laser dot burns through silk.
+func (k *Kitty) handleWebDamage() {
+	width, height := k.s.Size()
+	var spiders []*Spider
 	for _, o := range k.objects {
-		spider, ok := o.(*Spider)
-		if !ok {
-			continue
+		if s, ok := o.(*Spider); ok {
+			spiders = append(spiders, s)
+		}
+	}
+	if len(spiders) == 0 {
+		return
+	}
+	for _, o := range k.objects {
+		switch o := o.(type) {
+		case KittyPrey:
+			if !o.IsStuckInWeb() {
+				continue
+			}
+			px, py, ok := o.HitPoint(width, height)
+			if !ok {
+				continue
+			}
+			held := false
+			for _, s := range spiders {
+				if s.DamageWeb(px, py, 1, o.Struggle()) > 0 {
+					k.metrics.collision(o.Name() + "_web_tear")
+				}
+				held = held || s.WebHolds(px, py)
+			}
+			if !held {
+				o.BreakFree()
+			}
+		case *Snake:
+			for _, p := range o.BodyPoints() {
+				for _, s := range spiders {
+					if s.DamageWeb(p.X, p.Y, 1, 1) > 0 {
+						k.metrics.collision("snake_web_break")
+					}
+				}
+			}
+		case *LaserPointer:
+			if !k.config.LaserHitsSpiders {
+				continue
+			}
+			p, ok := o.Position(width, height)
+			if !ok {
+				continue
+			}
+			for _, s := range spiders {
+				if s.DamageWeb(p.X, p.Y, 1, 0.34) > 0 {
+					k.metrics.collision("laser_web_burn")
+				}
+			}
 		}
-		webPoints = append(webPoints, spider.GetWebPoints()...)
 	}
-	return webPoints
 }
 
 func absInt(v int) int {
@@ -0,0 +1,102 @@
+package kitty
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/vt"
+)
+
+// newMockScreen is an 80x24 mock terminal to pass to newKitty, so tests
+// never open the tty of the test runner.
+func newMockScreen() (tcell.Screen, error) {
+	return tcell.NewTerminfoScreenFromTty(vt.NewMockTerm())
+}
+
+// newTestKitty builds a Kitty on an 80x24 mock terminal. It has no
+// playthings until the test adds them to k.objects.
+func newTestKitty(t *testing.T, cfg KittyConfig) *Kitty {
+	t.Helper()
+	k, err := newKitty(cfg, newMockScreen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(k.s.Fini)
+	k.refreshWorld()
+	return k
+}
+
+func TestZeroConfigPlaysWithDefaults(t *testing.T) {
+	k := newTestKitty(t, KittyConfig{})
+	if k.config.SnakeCount != DefaultKittyConfig().SnakeCount {
+		t.Errorf("zero config has %d snakes, want the default %d", k.config.SnakeCount, DefaultKittyConfig().SnakeCount)
+	}
+}
+
+func TestIntensity(t *testing.T) {
+	// a config built by hand without an intensity plays at full intensity
+	k := newTestKitty(t, KittyConfig{SnakeCount: 1})
+	if k.Intensity != 1 {
+		t.Errorf("intensity %v, want the default 1", k.Intensity)
+	}
+	k = newTestKitty(t, KittyConfig{SnakeCount: 1, Intensity: 0.3})
+	if k.Intensity != 0.3 {
+		t.Errorf("intensity %v, want the configured 0.3", k.Intensity)
+	}
+	for _, v := range []float64{-0.5, 1.5, 2} {
+		cfg := DefaultKittyConfig()
+		cfg.Intensity = v
+		if _, err := newKitty(cfg, newMockScreen); err == nil {
+			t.Errorf("intensity %v was accepted", v)
+		}
+	}
+}
+
+func TestLaserShootsButterfliesAndMice(t *testing.T) {
+	k := newTestKitty(t, KittyConfig{SnakeCount: 1})
+	b := NewButterfly(DefaultButterflyConfig())
+	b.Spawn(k.world)
+	// butterflies fly in from off screen; start this one in the middle
+	b.x = 40
+	m := NewMouse(DefaultMouseConfig())
+	m.Spawn(k.world)
+	m.setState(mouseRunning, 10)
+	k.objects = []KittyPlayThing{b, m}
+
+	for _, target := range []KittyTarget{b, m} {
+		x, y, ok := target.HitPoint(k.screenWidth, k.screenHeight)
+		if !ok || !target.LaserTarget() {
+			t.Fatalf("%s is not on screen to shoot", target.Name())
+		}
+		laser := NewLaserPointer(DefaultLaserConfig())
+		laser.Spawn(k.world)
+		laser.x, laser.y = float64(x), float64(y)
+		k.objects = append(k.objects, laser)
+	}
+	k.handleLaserHits()
+
+	if b.Active() || m.Active() {
+		t.Error("a plaything under the laser was not despawned")
+	}
+	stats := k.Stats()
+	if stats.ButterfliesLasered != 1 || stats.MiceZapped != 1 {
+		t.Errorf("ButterfliesLasered = %d, MiceZapped = %d, want 1 each", stats.ButterfliesLasered, stats.MiceZapped)
+	}
+	if len(stats.Lasered) != 0 {
+		t.Errorf("Lasered = %v, want butterflies and mice counted on their own", stats.Lasered)
+	}
+}
+
+func TestNewSnakeDefaultsZeroWeights(t *testing.T) {
+	def := DefaultSnakeConfig()
+	s := NewSnake(SnakeConfig{WebWeight: -1})
+	if s.seekWeight != def.SeekWeight || s.fleeWeight != def.FleeWeight || s.separationWeight != def.SeparationWeight {
+		t.Errorf("zero weights = %v, %v, %v, want the defaults", s.seekWeight, s.fleeWeight, s.separationWeight)
+	}
+	if s.webWeight != 0 {
+		t.Errorf("negative web weight = %v, want it off", s.webWeight)
+	}
+	if s.eatChance != def.EatChance {
+		t.Errorf("zero eat chance = %v, want the default %v", s.eatChance, def.EatChance)
+	}
+}
@@ -1,57 +1,92 @@
 package kitty
 
 import (
+	"fmt"
+	"log/slog"
 	"math"
-	"math/rand"
-	"time"
 
 	"github.com/gdamore/tcell/v3"
 	"github.com/gdamore/tcell/v3/color"
 )
 
 type LaserPointer struct {
+	Lifecycle
 	Color tcell.Color
 
-	active        bool
-	respawnWait   int
-	initDelaySet  bool
-	initialDelayMax int
-
 	x       float64
 	y       float64
 	baseSpeed float64
 	speed     float64
+	speedScale float64
 	targetX   float64
 	targetY   float64
 	pauseTicks int
 	dashTicks  int
 	beamPhase  float64
 	fireTicks  int
+	intensity  float64
+	log        *slog.Logger
+	sprite     *Sprite
+	world      *World
+
+	phase      laserPhase
+	phaseTicks int
+	landX      float64
+	landY      float64
 }
 
-var laserRandSeeded bool
+type laserPhase int
 
-func (l *LaserPointer) Update(screen tcell.Screen) {
-	width, height := screen.Size()
-	if width <= 0 || height <= 0 {
-		return
+// A laser normally roams. When asked for a catch it evades for a moment,
+// slows down towards the landing spot and then lands there and fades out so
+// the cat gets to "catch" it.
+const (
+	laserRoam laserPhase = iota
+	laserEvade
+	laserSlow
+	laserLand
+)
+
+const laserLandTicks = 36
+
+func (p laserPhase) String() string {
+	switch p {
+	case laserRoam:
+		return "roam"
+	case laserEvade:
+		return "evade"
+	case laserSlow:
+		return "slow"
+	case laserLand:
+		return "land"
 	}
-	ensureLaserSeeded()
+	return "unknown"
+}
 
-	if !l.active && !l.initDelaySet {
-		maxDelay := l.initialDelayMax
-		if maxDelay <= 0 {
-			maxDelay = 80
-		}
-		l.respawnWait = rand.Intn(maxDelay + 1)
-		l.initDelaySet = true
+func (l *LaserPointer) setPhase(phase laserPhase) {
+	if l.phase == phase {
+		return
 	}
-	if l.respawnWait > 0 {
-		l.respawnWait--
+	l.log.Debug("laser phase", "from", l.phase.String(), "to", phase.String(),
+		"x", math.Round(l.x), "y", math.Round(l.y))
+	l.phase = phase
+}
+
+func (l *LaserPointer) Name() string {
+	return "laser"
+}
+
+func (l *LaserPointer) Layer() Layer {
+	return LayerLaser
+}
+
+func (l *LaserPointer) Update(w *World) {
+	l.world = w
+	width, height := w.Width, w.Height
+	if width <= 0 || height <= 0 {
 		return
 	}
-	if !l.active {
-		l.initLaser(width, height)
+	if !l.Tick(l, w) {
 		return
 	}
 
@@ -63,25 +98,40 @@ func (l *LaserPointer) Update(screen tcell.Screen) {
 		l.fireTicks--
 	}
 	l.beamPhase += 0.35
+
+	switch l.phase {
+	case laserEvade:
+		l.updateEvade(width, height)
+		return
+	case laserSlow:
+		l.updateSlow(width, height)
+		return
+	case laserLand:
+		l.updateLand()
+		return
+	}
+
+	act := activity(l.intensity)
 	if l.dashTicks > 0 {
 		l.dashTicks--
-		l.speed = randRange(2.5, 4.0)
+		l.speed = l.world.Range(2.5, 4.0) * act * l.speedScale
 	} else {
-		l.speed += (l.baseSpeed - l.speed) * 0.12
-		if rand.Float64() < 0.01 {
-			l.pauseTicks = 4 + rand.Intn(8)
+		l.speed += (l.baseSpeed*act - l.speed) * 0.12
+		// a calm laser lingers more and dashes less
+		if l.world.Float64() < 0.01/act {
+			l.pauseTicks = 4 + l.world.Intn(8)
 		}
-		if rand.Float64() < 0.05 {
-			l.dashTicks = 6 + rand.Intn(12)
+		if l.world.Float64() < 0.05*act {
+			l.dashTicks = 6 + l.world.Intn(12)
 		}
 	}
 
 	dx := l.targetX - l.x
 	dy := l.targetY - l.y
 	dist := math.Hypot(dx, dy)
-	if dist < 1.2 || rand.Float64() < 0.04 {
-		l.targetX = randRange(1, float64(width-2))
-		l.targetY = randRange(1, float64(height-2))
+	if dist < 1.2 || l.world.Float64() < 0.04 {
+		l.targetX = l.world.Range(1, float64(width-2))
+		l.targetY = l.world.Range(1, float64(height-2))
 		return
 	}
 	step := l.speed / math.Max(dist, 0.001)
@@ -89,22 +139,113 @@ func (l *LaserPointer) Update(screen tcell.Screen) {
 	l.y += dy * step
 }
 
-func (l *LaserPointer) Draw(screen tcell.Screen) {
-	if !l.active {
+// updateEvade is one last frantic burst: short dashes, sharp turns and no
+// pauses before the laser gives up.
+func (l *LaserPointer) updateEvade(width, height int) {
+	l.phaseTicks--
+	if l.phaseTicks <= 0 {
+		l.setPhase(laserSlow)
 		return
 	}
-	width, height := screen.Size()
-	cx := int(math.Round(l.x))
-	cy := int(math.Round(l.y))
-	if cx < 0 || cy < 0 || cx >= width || cy >= height {
+	l.speed = l.world.Range(2.0, 3.5) * l.speedScale
+	dx := l.targetX - l.x
+	dy := l.targetY - l.y
+	dist := math.Hypot(dx, dy)
+	if dist < 1.2 || l.world.Float64() < 0.2 {
+		l.targetX = l.world.Range(1, float64(width-2))
+		l.targetY = l.world.Range(1, float64(height-2))
+		return
+	}
+	step := l.speed / math.Max(dist, 0.001)
+	l.x += dx * step
+	l.y += dy * step
+}
+
+// updateSlow heads for the landing spot, slowing and hesitating on the way.
+func (l *LaserPointer) updateSlow(width, height int) {
+	l.targetX, l.targetY = l.landingPoint(width, height)
+	dx := l.targetX - l.x
+	dy := l.targetY - l.y
+	dist := math.Hypot(dx, dy)
+	if dist < 0.8 {
+		l.x = l.targetX
+		l.y = l.targetY
+		l.setPhase(laserLand)
+		l.phaseTicks = laserLandTicks
+		l.world.Effects.EmitText(fxCaughtText, "caught!", l.x, l.y-2)
+		return
+	}
+	l.speed += (0.35 - l.speed) * 0.08
+	if l.world.Float64() < 0.04 {
+		l.pauseTicks = 3 + l.world.Intn(6)
+	}
+	step := math.Min(l.speed, dist) / dist
+	l.x += dx * step
+	l.y += dy * step
+}
+
+func (l *LaserPointer) updateLand() {
+	l.phaseTicks--
+	if l.phaseTicks > 0 {
 		return
 	}
+	l.Despawn(DespawnGone)
+}
+
+func (l *LaserPointer) Spawn(w *World) {
+	l.world = w
+	l.Start()
+	l.sprite = w.Sprites.Pick("laser", w)
+	l.initLaser(w.Width, w.Height)
+}
+
+// Despawn switches the laser off. It normally goes after landing.
+func (l *LaserPointer) Despawn(reason DespawnReason) {
+	wait := 0
+	if reason != DespawnRemoved {
+		wait = calmWait(120+l.world.Intn(120), l.intensity)
+	}
+	l.setPhase(laserRoam)
+	l.fireTicks = 0
+	l.Stop(wait)
+}
+
+// Bounds covers the dot and its glow, or the landing ring while it lands.
+// The beam is left out.
+func (l *LaserPointer) Bounds() Rect {
+	p, ok := l.Position(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return Rect{}
+	}
+	if l.phase == laserLand {
+		return rectAround(p.X, p.Y, 8, 4)
+	}
+	return rectAround(p.X, p.Y, 1, 1)
+}
+
+func (l *LaserPointer) landingPoint(width, height int) (float64, float64) {
+	x := clampFloat(l.landX, 0, 1) * float64(width-1)
+	y := clampFloat(l.landY, 0, 1) * float64(height-1)
+	return math.Round(x), math.Round(y)
+}
+
+func (l *LaserPointer) Draw(c *Canvas) {
+	width, height := c.Size()
+	p, ok := l.Position(width, height)
+	if !ok {
+		return
+	}
+	cx, cy := p.X, p.Y
 
 	fg := l.Color
 	if fg == tcell.ColorDefault || fg == 0 {
 		fg = color.Red
 		l.Color = fg
 	}
+	if l.phase == laserLand {
+		l.drawLanding(c, cx, cy, fg)
+		return
+	}
 	glow := color.DarkRed
 	// draw beam from bottom center to the laser point only while firing
 	if l.fireTicks > 0 {
@@ -117,26 +258,36 @@ func (l *LaserPointer) Draw(screen tcell.Screen) {
 				beamColor = fg
 				beamRune = tcell.RuneBlock
 			}
-			drawLaserBeam(screen, beamX, beamY, cx, cy, beamRune, beamColor)
+			drawLaserBeam(c, beamX, beamY, cx, cy, beamRune, beamColor)
 		}
 	}
 
-	screen.SetContent(cx, cy, tcell.RuneBlock, nil, tcell.StyleDefault.Foreground(fg))
-	for _, p := range []Point{{X: cx - 1, Y: cy}, {X: cx + 1, Y: cy}, {X: cx, Y: cy - 1}, {X: cx, Y: cy + 1}} {
-		if p.X < 0 || p.Y < 0 || p.X >= width || p.Y >= height {
-			continue
-		}
-		screen.SetContent(p.X, p.Y, tcell.RuneBlock, nil, tcell.StyleDefault.Foreground(glow))
-	}
-	for _, p := range []Point{{X: cx - 1, Y: cy - 1}, {X: cx + 1, Y: cy - 1}, {X: cx - 1, Y: cy + 1}, {X: cx + 1, Y: cy + 1}} {
-		if p.X < 0 || p.Y < 0 || p.X >= width || p.Y >= height {
+	l.sprite.Frame("dot").Draw(c, cx, cy, fg, glow)
+}
+
+// drawLanding fades the dot out where it landed: it shrinks and dims while
+// a ring ripples outwards, like the dot sinking into the floor.
+func (l *LaserPointer) drawLanding(c *Canvas, cx, cy int, fg tcell.Color) {
+	u := 1 - float64(l.phaseTicks)/laserLandTicks
+	ramp := []tcell.Color{fg, color.DarkRed, color.Maroon, color.DarkSlateGray}
+	runes := []rune{tcell.RuneBlock, '●', '•', tcell.RuneBullet}
+	i := min(int(u*float64(len(ramp))), len(ramp)-1)
+	c.Set(cx, cy, runes[i], ramp[i])
+
+	radius := 1 + u*3
+	ringColor := ramp[min(i+1, len(ramp)-1)]
+	for a := 0; a < 12; a++ {
+		angle := float64(a) / 12 * 2 * math.Pi
+		x := cx + int(math.Round(math.Cos(angle)*radius*2))
+		y := cy + int(math.Round(math.Sin(angle)*radius))
+		if x == cx && y == cy {
 			continue
 		}
-		screen.SetContent(p.X, p.Y, tcell.RuneBullet, nil, tcell.StyleDefault.Foreground(glow))
+		c.Set(x, y, tcell.RuneBullet, ringColor)
 	}
 }
 
-func drawLaserBeam(screen tcell.Screen, x0, y0, x1, y1 int, r rune, fg tcell.Color) {
+func drawLaserBeam(c *Canvas, x0, y0, x1, y1 int, r rune, fg tcell.Color) {
 	dx := absInt(x1 - x0)
 	dy := -absInt(y1 - y0)
 	sx := -1
@@ -149,7 +300,7 @@ func drawLaserBeam(screen tcell.Screen, x0, y0, x1, y1 int, r rune, fg tcell.Col
 	}
 	err := dx + dy
 	for {
-		screen.SetContent(x0, y0, r, nil, tcell.StyleDefault.Foreground(fg))
+		c.Set(x0, y0, r, fg)
 		if x0 == x1 && y0 == y1 {
 			break
 		}
@@ -166,7 +317,7 @@ func drawLaserBeam(screen tcell.Screen, x0, y0, x1, y1 int, r rune, fg tcell.Col
 }
 
 func (l *LaserPointer) Position(width, height int) (Point, bool) {
-	if !l.active {
+	if !l.Active() {
 		return Point{}, false
 	}
 	cx := int(math.Round(l.x))
@@ -177,22 +328,63 @@ func (l *LaserPointer) Position(width, height int) (Point, bool) {
 	return Point{X: cx, Y: cy}, true
 }
 
+// TriggerFire shows the beam for a few ticks and throws sparks off the dot.
 func (l *LaserPointer) TriggerFire() {
 	if l.fireTicks < 3 {
 		l.fireTicks = 3
 	}
+	l.world.Effects.Emit(fxSpark, l.x, l.y)
+}
+
+// Firing reports whether the beam is showing.
+func (l *LaserPointer) Firing() bool {
+	return l.Active() && l.fireTicks > 0
+}
+
+// Catch starts the ending sequence: evade, slow down and land at the
+// configured spot. It does nothing if the laser is already landing.
+func (l *LaserPointer) Catch() {
+	if !l.Active() || l.phase != laserRoam {
+		return
+	}
+	l.setPhase(laserEvade)
+	l.phaseTicks = 20 + l.world.Intn(20)
+	l.dashTicks = 0
+	l.pauseTicks = 0
+}
+
+// Leave ends the laser with a catch so the cat gets closure.
+func (l *LaserPointer) Leave() {
+	l.Lifecycle.Leave()
+	l.Catch()
+}
+
+func (l *LaserPointer) SetIntensity(intensity float64) {
+	l.intensity = intensity
+}
+
+func (l *LaserPointer) SetLogger(log *slog.Logger) {
+	l.log = log
+}
+
+func (l *LaserPointer) DebugInfo() (int, int, string, bool) {
+	if !l.Active() {
+		return 0, 0, "", false
+	}
+	info := fmt.Sprintf("laser %s v=%.1f tgt=(%.0f,%.0f)", l.phase, l.speed, l.targetX, l.targetY)
+	return int(math.Round(l.x)), int(math.Round(l.y)), info, true
 }
 
 func (l *LaserPointer) initLaser(width, height int) {
-	l.active = true
-	l.baseSpeed = randRange(1.0, 2.2)
+	l.baseSpeed = l.world.Range(1.0, 2.2) * l.speedScale
 	l.speed = l.baseSpeed
-	l.x = randRange(1, float64(width-2))
-	l.y = randRange(1, float64(height-2))
-	l.targetX = randRange(1, float64(width-2))
-	l.targetY = randRange(1, float64(height-2))
+	l.x = l.world.Range(1, float64(width-2))
+	l.y = l.world.Range(1, float64(height-2))
+	l.targetX = l.world.Range(1, float64(width-2))
+	l.targetY = l.world.Range(1, float64(height-2))
 	l.pauseTicks = 0
 	l.dashTicks = 0
+	l.setPhase(laserRoam)
 	if l.Color == tcell.ColorDefault || l.Color == 0 {
 		l.Color = color.Red
 	}
@@ -202,15 +394,16 @@ func NewLaserPointer(cfg LaserConfig) *LaserPointer {
 	if cfg.InitialDelayMax <= 0 {
 		cfg.InitialDelayMax = 80
 	}
-	return &LaserPointer{
-		Color:           cfg.Color,
-		initialDelayMax: cfg.InitialDelayMax,
+	if cfg.Speed <= 0 {
+		cfg.Speed = 1.0
 	}
-}
-
-func ensureLaserSeeded() {
-	if !laserRandSeeded {
-		rand.Seed(time.Now().UnixNano())
-		laserRandSeeded = true
+	return &LaserPointer{
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		Color:     cfg.Color,
+		intensity: 1.0,
+		log:       discardLogger,
+		landX:     cfg.LandX,
+		landY:     cfg.LandY,
+		speedScale: cfg.Speed,
 	}
 }
@@ -0,0 +1,134 @@
+package kitty
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Layer is how far up the screen a plaything is drawn. A cell drawn on a
+// higher layer is never covered by one drawn on a lower layer.
+type Layer int
+
+const (
+	LayerBackground Layer = iota
+	// LayerWebs holds spider webs and strings.
+	LayerWebs
+	LayerCritters
+	LayerLaser
+	LayerEffects
+	// LayerHUD is for overlays such as --debug.
+	LayerHUD
+)
+
+var layerNames = []string{"background", "webs", "critters", "laser", "effects", "hud"}
+
+func (l Layer) String() string {
+	if l < 0 || int(l) >= len(layerNames) {
+		return "unknown"
+	}
+	return layerNames[l]
+}
+
+// parseLayer looks up a layer by name.
+func parseLayer(name string) (Layer, bool) {
+	for i, n := range layerNames {
+		if n == name {
+			return Layer(i), true
+		}
+	}
+	return 0, false
+}
+
+// drawRank orders playthings of different types that share a layer, so
+// that, for example, butterflies are drawn over snakes.
+var drawRank = map[string]int{
+	"string":    0,
+	"wand":      1,
+	"ball":      2,
+	"yarn":      3,
+	"fish":      4,
+	"mouse":     5,
+	"snake":     6,
+	"spider":    7,
+	"butterfly": 8,
+	"fly":       9,
+	"firefly":   10,
+	"bird":      11,
+	"laser":     12,
+}
+
+// ParseLayers parses a comma separated list of "type=layer" entries, e.g.
+// "laser=effects,spider=webs", into a map for KittyConfig.Layers. Each type
+// must be built in or registered.
+func ParseLayers(spec string) (map[string]Layer, error) {
+	layers := map[string]Layer{}
+	if strings.TrimSpace(spec) == "" {
+		return layers, nil
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		name, layerName, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("bad layer %q: want type=layer", entry)
+		}
+		name = strings.TrimSpace(name)
+		if _, ok := drawRank[name]; !ok && !slices.Contains(Registered(), name) {
+			return nil, fmt.Errorf("unknown plaything %q (want %s)", name, strings.Join(playthingNames(), ", "))
+		}
+		layer, ok := parseLayer(strings.TrimSpace(layerName))
+		if !ok {
+			return nil, fmt.Errorf("unknown layer %q (want %s)", layerName, strings.Join(layerNames, ", "))
+		}
+		layers[name] = layer
+	}
+	return layers, nil
+}
+
+// playthingNames lists the built-in and registered plaything types, sorted.
+func playthingNames() []string {
+	names := Registered()
+	for name := range drawRank {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// layerOrder is the draw order Kitty uses: each plaything type's layer,
+// possibly moved by KittyConfig.Layers.
+type layerOrder map[string]Layer
+
+// newLayerOrder copies the layers a config moves plaything types to,
+// checking each is a real layer.
+func newLayerOrder(layers map[string]Layer) (layerOrder, error) {
+	order := layerOrder{}
+	for name, l := range layers {
+		if l < LayerBackground || l > LayerHUD {
+			return nil, fmt.Errorf("%s: unknown layer %d", name, l)
+		}
+		order[name] = l
+	}
+	return order, nil
+}
+
+func (lo layerOrder) layer(o KittyPlayThing) Layer {
+	if l, ok := lo[o.Name()]; ok {
+		return l
+	}
+	return o.Layer()
+}
+
+// sorted returns the playthings in the order they are drawn: by layer, then
+// by type, then in the order they were spawned.
+func (lo layerOrder) sorted(objects []KittyPlayThing) []KittyPlayThing {
+	out := append([]KittyPlayThing(nil), objects...)
+	sort.SliceStable(out, func(i, j int) bool {
+		li, lj := lo.layer(out[i]), lo.layer(out[j])
+		if li != lj {
+			return li < lj
+		}
+		return drawRank[out[i].Name()] < drawRank[out[j].Name()]
+	})
+	return out
+}
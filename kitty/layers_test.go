@@ -0,0 +1,39 @@
+package kitty
+
+import (
+	"testing"
+)
+
+func TestParseLayers(t *testing.T) {
+	layers, err := ParseLayers("laser=effects, spider = webs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 2 || layers["laser"] != LayerEffects || layers["spider"] != LayerWebs {
+		t.Errorf("ParseLayers = %v", layers)
+	}
+	for _, bad := range []string{"laser", "laser=sky", "lazer=effects", "=webs"} {
+		if _, err := ParseLayers(bad); err == nil {
+			t.Errorf("ParseLayers(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestNewRejectsUnknownLayer(t *testing.T) {
+	cfg := DefaultKittyConfig()
+	cfg.Layers = map[string]Layer{"laser": LayerHUD + 1}
+	if _, err := newKitty(cfg, newMockScreen); err == nil {
+		t.Error("New accepted a layer past the HUD")
+	}
+}
+
+func TestParseLayersTakesRegisteredTypes(t *testing.T) {
+	registerForTest(t, "target", newTarget)
+	layers, err := ParseLayers("target=hud")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layers["target"] != LayerHUD {
+		t.Errorf("ParseLayers = %v", layers)
+	}
+}
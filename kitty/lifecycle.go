@@ -0,0 +1,74 @@
+package kitty
+
+// Lifecycle runs the timers every plaything shares: a random delay before it
+// first appears, a wait between despawning and spawning again, and staying
+// away for good once it has been asked to leave. Embed it, call Tick at the
+// top of Update, Start from Spawn and Stop from Despawn; the plaything is
+// left with only its motion and rendering to write.
+type Lifecycle struct {
+	// InitialDelayMax is the longest random delay, in ticks, before the
+	// first spawn.
+	InitialDelayMax int
+
+	active   bool
+	leaving  bool
+	delaySet bool
+	wait     int
+	spawns   int
+}
+
+// Tick counts the timers down and spawns p once they run out. It reports
+// whether p was already on screen and should move this tick.
+func (l *Lifecycle) Tick(p KittyPlayThing, w *World) bool {
+	if l.active {
+		return true
+	}
+	if l.leaving {
+		return false
+	}
+	if !l.delaySet {
+		l.delaySet = true
+		if l.InitialDelayMax > 0 {
+			l.wait = w.Intn(l.InitialDelayMax + 1)
+		}
+	}
+	if l.wait > 0 {
+		l.wait--
+		return false
+	}
+	p.Spawn(w)
+	return false
+}
+
+// Start marks the plaything as on screen.
+func (l *Lifecycle) Start() {
+	l.active = true
+	l.spawns++
+}
+
+// Stop takes the plaything off screen for at least wait ticks.
+func (l *Lifecycle) Stop(wait int) {
+	l.active = false
+	l.wait = wait
+}
+
+func (l *Lifecycle) Active() bool {
+	return l.active
+}
+
+func (l *Lifecycle) Spawns() int {
+	return l.spawns
+}
+
+// Leave stops the plaything from spawning again.
+func (l *Lifecycle) Leave() {
+	l.leaving = true
+}
+
+func (l *Lifecycle) Leaving() bool {
+	return l.leaving
+}
+
+func (l *Lifecycle) HasLeft() bool {
+	return l.leaving && !l.active
+}
@@ -0,0 +1,173 @@
+package kitty
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// frameBudget is how long one tick of the play loop is meant to take.
+const frameBudget = 55 * time.Millisecond
+
+// metrics exposes the play loop to Prometheus. Every Kitty gets its own
+// registry so several can live in one process. A nil *metrics is valid and
+// records nothing.
+type metrics struct {
+	registry      *prometheus.Registry
+	frameSeconds  *prometheus.HistogramVec
+	objects       *prometheus.GaugeVec
+	collisions    *prometheus.CounterVec
+	droppedFrames prometheus.Counter
+	inputEvents   *prometheus.CounterVec
+
+	// objectTypes are the type labels setObjects set last time.
+	objectTypes map[string]bool
+
+	listener net.Listener
+	server   *http.Server
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry:    prometheus.NewRegistry(),
+		objectTypes: map[string]bool{},
+		frameSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "go_kitty_frame_seconds",
+			Help:    "Time spent in each pass of a frame.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .055, .1, .25},
+		}, []string{"pass"}),
+		objects: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "go_kitty_objects",
+			Help: "Playthings on screen by type.",
+		}, []string{"type"}),
+		collisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "go_kitty_collisions_total",
+			Help: "Collisions between playthings by kind.",
+		}, []string{"kind"}),
+		droppedFrames: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "go_kitty_dropped_frames_total",
+			Help: "Frames skipped because a tick ran over its budget.",
+		}),
+		inputEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "go_kitty_input_events_total",
+			Help: "Keyboard and mouse events received.",
+		}, []string{"kind"}),
+	}
+	m.registry.MustRegister(
+		m.frameSeconds,
+		m.objects,
+		m.collisions,
+		m.droppedFrames,
+		m.inputEvents,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// listen binds the metrics address up front so a bad address is reported
+// before the screen is taken over.
+func (m *metrics) listen(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	m.listener = l
+	m.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	return nil
+}
+
+func (m *metrics) serve(log *slog.Logger) {
+	if m == nil || m.server == nil {
+		return
+	}
+	go func() {
+		if err := m.server.Serve(m.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("metrics server stopped", "addr", m.listener.Addr().String(), "err", err)
+			m.listener.Close()
+		}
+	}()
+}
+
+func (m *metrics) shutdown() {
+	if m == nil || m.server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	m.server.Shutdown(ctx)
+}
+
+// close releases the listener when the metrics are never served.
+func (m *metrics) close() {
+	if m == nil || m.listener == nil {
+		return
+	}
+	m.listener.Close()
+}
+
+func (m *metrics) observeFrame(pass string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.frameSeconds.WithLabelValues(pass).Observe(d.Seconds())
+}
+
+// observeTick counts every whole frame budget a tick overran as a dropped frame.
+func (m *metrics) observeTick(d time.Duration) {
+	if m == nil || d <= frameBudget {
+		return
+	}
+	m.droppedFrames.Add(float64(d / frameBudget))
+}
+
+func (m *metrics) setObjects(objects []KittyPlayThing) {
+	if m == nil {
+		return
+	}
+	// count first and set each label once, so a scrape never sees the
+	// gauge half filled
+	// playthings waiting to spawn keep their type at 0
+	counts := map[string]int{}
+	for _, o := range objects {
+		counts[o.Name()] += 0
+		if o.Active() {
+			counts[o.Name()]++
+		}
+	}
+	for name, n := range counts {
+		m.objects.WithLabelValues(name).Set(float64(n))
+	}
+	for name := range m.objectTypes {
+		if _, ok := counts[name]; !ok {
+			m.objects.DeleteLabelValues(name)
+			delete(m.objectTypes, name)
+		}
+	}
+	for name := range counts {
+		m.objectTypes[name] = true
+	}
+}
+
+func (m *metrics) collision(kind string) {
+	if m == nil {
+		return
+	}
+	m.collisions.WithLabelValues(kind).Inc()
+}
+
+func (m *metrics) input(kind string) {
+	if m == nil {
+		return
+	}
+	m.inputEvents.WithLabelValues(kind).Inc()
+}
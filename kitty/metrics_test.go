@@ -0,0 +1,93 @@
+package kitty
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMetricsScrape(t *testing.T) {
+	m := newMetrics()
+	if err := m.listen("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	m.serve(discardLogger)
+	defer m.shutdown()
+
+	// vectors only show up once they have a label
+	m.observeFrame("update", frameBudget/2)
+	w := NewWorld(1)
+	w.Width, w.Height = 80, 24
+	str := NewSwayString(DefaultSwayStringConfig())
+	str.Spawn(w)
+	m.setObjects([]KittyPlayThing{str})
+	m.collision("laser_butterfly")
+	m.observeTick(3 * frameBudget)
+	m.input("key")
+
+	resp, err := http.Get("http://" + m.listener.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{
+		"go_kitty_frame_seconds",
+		"go_kitty_objects",
+		"go_kitty_collisions_total",
+		"go_kitty_dropped_frames_total",
+		"go_kitty_input_events_total",
+	} {
+		if !strings.Contains(string(body), "\n"+name) {
+			t.Errorf("scrape is missing %s", name)
+		}
+	}
+	if !strings.Contains(string(body), `go_kitty_objects{type="string"} 1`) {
+		t.Errorf("scrape is missing the string count:\n%s", body)
+	}
+}
+
+func TestMetricsSetObjectsDropsGoneTypes(t *testing.T) {
+	m := newMetrics()
+	w := NewWorld(1)
+	w.Width, w.Height = 80, 24
+	spawned := func(p KittyPlayThing) KittyPlayThing {
+		p.Spawn(w)
+		return p
+	}
+	m.setObjects([]KittyPlayThing{
+		spawned(NewSwayString(DefaultSwayStringConfig())),
+		spawned(NewSwayString(DefaultSwayStringConfig())),
+		spawned(NewButterfly(DefaultButterflyConfig())),
+	})
+	// a fish still waiting to spawn is in the scene but not on screen
+	m.setObjects([]KittyPlayThing{
+		spawned(NewSwayString(DefaultSwayStringConfig())),
+		NewSwayString(DefaultSwayStringConfig()),
+		NewFishSchool(DefaultFishConfig()),
+	})
+
+	families, err := m.registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]float64{}
+	for _, f := range families {
+		if f.GetName() != "go_kitty_objects" {
+			continue
+		}
+		for _, metric := range f.GetMetric() {
+			got[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+		}
+	}
+	if len(got) != 2 || got["string"] != 1 || got["fish"] != 0 {
+		t.Errorf("objects = %v, want string=1 and fish=0", got)
+	}
+}
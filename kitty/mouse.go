@@ -0,0 +1,449 @@
+package kitty
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Mouse scurries between holes at the screen edges, along the floor and up
+// the side walls. It peeks out of its hole before making a run for the next
+// one and freezes when the laser gets close.
+type Mouse struct {
+	Lifecycle
+	Color tcell.Color
+
+	state       mouseState
+	stateTicks  int
+	u           float64
+	target      float64
+	dir         int
+	v           float64
+	targetV     float64
+	pauseTicks  int
+	dartTicks   int
+	twitchTicks int
+	calmTicks   int
+	webChance   float64
+	webCooldown int
+	intensity   float64
+	log         *slog.Logger
+	sprite      *Sprite
+	world       *World
+}
+
+type mouseState int
+
+// A mouse starts hidden in its hole, peeks out for a while and then runs for
+// another hole. A laser nearby makes a peeking mouse duck back in and a
+// running one freeze until the laser moves on.
+const (
+	mouseHidden mouseState = iota
+	mousePeeking
+	mouseRunning
+	mouseFrozen
+	mouseStuck
+)
+
+func (s mouseState) String() string {
+	switch s {
+	case mouseHidden:
+		return "hidden"
+	case mousePeeking:
+		return "peeking"
+	case mouseRunning:
+		return "running"
+	case mouseFrozen:
+		return "frozen"
+	case mouseStuck:
+		return "stuck"
+	}
+	return "unknown"
+}
+
+// mouseFreezeRadius is how close, in rows, the laser gets before a mouse
+// freezes. Columns count half as much.
+const mouseFreezeRadius = 5
+
+func (m *Mouse) setState(state mouseState, ticks int) {
+	if m.state != state {
+		p := m.head()
+		m.log.Debug("mouse state", "from", m.state.String(), "to", state.String(), "x", p.X, "y", p.Y)
+	}
+	m.state = state
+	m.stateTicks = ticks
+}
+
+func (m *Mouse) Name() string {
+	return "mouse"
+}
+
+func (m *Mouse) Layer() Layer {
+	return LayerCritters
+}
+
+func (m *Mouse) Update(w *World) {
+	m.world = w
+	if w.Width <= 0 || w.Height <= 0 {
+		return
+	}
+	if !m.Tick(m, w) {
+		return
+	}
+	path := newMousePath(w.Width, w.Height)
+	m.u = clampFloat(m.u, 0, path.length())
+	m.target = clampFloat(m.target, 0, path.length())
+	if m.webCooldown > 0 {
+		m.webCooldown--
+	}
+	if m.stateTicks > 0 {
+		m.stateTicks--
+	}
+
+	switch m.state {
+	case mouseHidden:
+		if m.Leaving() {
+			m.Despawn(DespawnGone)
+		} else if m.stateTicks == 0 {
+			m.setState(mousePeeking, 15+m.world.Intn(30))
+		}
+	case mousePeeking:
+		switch {
+		case m.Leaving():
+			m.Despawn(DespawnGone)
+		case m.laserNear():
+			m.setState(mouseHidden, 20+m.world.Intn(40))
+		case m.stateTicks == 0:
+			m.setState(mouseRunning, 0)
+			m.dartTicks = 4 + m.world.Intn(6)
+		}
+	case mouseFrozen:
+		if m.laserNear() {
+			m.calmTicks = 0
+		} else {
+			m.calmTicks++
+		}
+		// bolt once the laser moves on, or when it hangs around too long
+		if m.calmTicks > 6 || m.stateTicks == 0 {
+			m.setState(mouseRunning, 0)
+			m.dartTicks = 6 + m.world.Intn(8)
+		}
+	case mouseStuck:
+		if m.stateTicks == 0 {
+			m.BreakFree()
+		}
+	case mouseRunning:
+		if m.laserNear() && m.dartTicks == 0 {
+			m.setState(mouseFrozen, 40+m.world.Intn(40))
+			m.calmTicks = 0
+			m.v = 0
+			return
+		}
+		m.run(path)
+	}
+}
+
+// run moves the mouse along the path with the pauses, darts and twitches of
+// a bouncy ball, only slower up the walls.
+func (m *Mouse) run(path mousePath) {
+	if m.pauseTicks > 0 {
+		m.pauseTicks--
+		m.targetV = 0
+	} else if m.dartTicks > 0 || m.Leaving() {
+		if m.dartTicks > 0 {
+			m.dartTicks--
+		}
+		m.targetV = m.world.Range(2.0, 3.0)
+	} else {
+		m.targetV = clampFloat(m.targetV+(m.world.Float64()-0.5)*0.1, 0.5, 1.2)
+		if m.world.Float64() < 0.03 {
+			m.pauseTicks = 4 + m.world.Intn(12)
+		}
+		if m.world.Float64() < 0.02 {
+			m.dartTicks = 4 + m.world.Intn(8)
+		}
+	}
+	if m.twitchTicks > 0 {
+		m.twitchTicks--
+		m.targetV *= 1.15
+	} else if m.world.Float64() < 0.01 {
+		m.twitchTicks = 4 + m.world.Intn(6)
+	}
+	m.v += (m.targetV - m.v) * 0.3
+	speed := m.v * activity(m.intensity)
+	if path.onWall(m.u) {
+		speed *= 0.6
+	}
+	m.u += speed * float64(m.dir)
+	if (m.dir > 0 && m.u >= m.target) || (m.dir < 0 && m.u <= m.target) {
+		m.u = m.target
+		m.Despawn(DespawnGone)
+	}
+}
+
+// laserNear reports whether a laser dot is close to the mouse's head.
+func (m *Mouse) laserNear() bool {
+	head := m.head()
+	for _, l := range m.world.Lasers {
+		dx := float64(l.X-head.X) / cellAspect
+		dy := float64(l.Y - head.Y)
+		if math.Hypot(dx, dy) <= mouseFreezeRadius {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Mouse) Spawn(w *World) {
+	m.world = w
+	m.Start()
+	m.sprite = w.Sprites.Pick("mouse", w)
+	path := newMousePath(w.Width, w.Height)
+	holes := path.holes()
+	from := m.world.Intn(len(holes))
+	to := (from + 1 + m.world.Intn(len(holes)-1)) % len(holes)
+	m.u = holes[from]
+	m.target = holes[to]
+	m.dir = 1
+	if m.target < m.u {
+		m.dir = -1
+	}
+	m.v = 0
+	m.targetV = m.world.Range(0.6, 1.0)
+	m.pauseTicks = 0
+	m.dartTicks = 0
+	m.twitchTicks = 0
+	m.webCooldown = 0
+	m.setState(mouseHidden, 10+m.world.Intn(20))
+}
+
+// Despawn takes the mouse off screen. It normally ducks into a hole; a
+// lasered mouse bursts and an eaten one flashes.
+func (m *Mouse) Despawn(reason DespawnReason) {
+	head := m.head()
+	wait := 0
+	switch reason {
+	case DespawnGone:
+		wait = calmWait(60+m.world.Intn(120), m.intensity)
+	case DespawnHit:
+		m.log.Debug("mouse zapped", "x", head.X, "y", head.Y)
+		m.world.Effects.Emit(fxBurst, float64(head.X), float64(head.Y))
+		wait = 60 + m.world.Intn(120)
+	case DespawnEaten:
+		m.log.Debug("mouse eaten", "x", head.X, "y", head.Y)
+		m.world.Effects.Emit(fxCaught, float64(head.X), float64(head.Y))
+		wait = calmWait(120+m.world.Intn(120), m.intensity)
+	}
+	m.setState(mouseHidden, 0)
+	m.Stop(wait)
+}
+
+// head is where the mouse's nose is. A peeking mouse only has its head one
+// cell out of the hole.
+func (m *Mouse) head() Point {
+	if m.world == nil {
+		return Point{}
+	}
+	path := newMousePath(m.world.Width, m.world.Height)
+	if m.state == mousePeeking {
+		return path.at(m.u + float64(m.dir))
+	}
+	return path.at(m.u)
+}
+
+// HitPoint is the mouse's head while it is out of its hole.
+func (m *Mouse) HitPoint(width, height int) (int, int, bool) {
+	if !m.Active() || m.state == mouseHidden {
+		return 0, 0, false
+	}
+	p := m.head()
+	if p.X < 0 || p.Y < 0 || p.X >= width || p.Y >= height {
+		return 0, 0, false
+	}
+	return p.X, p.Y, true
+}
+
+// LaserTarget lets the laser zap the mouse while it is out of its hole.
+func (m *Mouse) LaserTarget() bool {
+	return m.Active() && m.state != mouseHidden
+}
+
+func (m *Mouse) Bounds() Rect {
+	x, y, ok := m.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return Rect{}
+	}
+	return rectAround(x, y, 2, 2)
+}
+
+func (m *Mouse) Draw(c *Canvas) {
+	width, height := c.Size()
+	if !m.Active() || m.state == mouseHidden || width <= 0 || height <= 0 {
+		return
+	}
+	fg := m.Color
+	if fg == tcell.ColorDefault || fg == 0 {
+		fg = color.Silver
+	}
+	path := newMousePath(width, height)
+	nose := color.Pink
+	switch m.state {
+	case mouseFrozen, mouseStuck:
+		// trembling whiskers
+		if m.world.Float64() < 0.3 {
+			nose = color.White
+		}
+	}
+
+	sprite := m.sprite
+	if m.state == mousePeeking {
+		hole := path.at(m.u)
+		sprite.Frame("hole").Draw(c, hole.X, hole.Y, fg, nose)
+		// the head pops in and out while it looks around
+		if m.stateTicks%8 < 5 {
+			head := path.at(m.u + float64(m.dir))
+			sprite.Frame(mouseHeadFrame(hole, head)).Draw(c, head.X, head.Y, fg, nose)
+		}
+		return
+	}
+
+	head := path.at(m.u)
+	body := path.at(m.u - float64(m.dir))
+	tail := path.at(m.u - 2*float64(m.dir))
+	if tail != body {
+		sprite.Frame("tail").Draw(c, tail.X, tail.Y, fg, nose)
+	}
+	if body != head {
+		sprite.Frame("body").Draw(c, body.X, body.Y, fg, nose)
+	}
+	sprite.Frame(mouseHeadFrame(body, head)).Draw(c, head.X, head.Y, fg, nose)
+}
+
+// mouseHeadFrame picks the head frame that points the mouse's nose the way
+// it is going.
+func mouseHeadFrame(from, to Point) string {
+	switch {
+	case to.X > from.X:
+		return "right"
+	case to.X < from.X:
+		return "left"
+	case to.Y < from.Y:
+		return "up"
+	case to.Y > from.Y:
+		return "down"
+	}
+	return "still"
+}
+
+// StickToWeb catches the mouse in a web it ran into now and then. Most of
+// the time it squeezes through.
+func (m *Mouse) StickToWeb() bool {
+	if m.webCooldown > 0 || (m.state != mouseRunning && m.state != mouseFrozen) {
+		return false
+	}
+	m.webCooldown = 30
+	if m.world.Float64() >= m.webChance {
+		return false
+	}
+	m.setState(mouseStuck, 80+m.world.Intn(80))
+	return true
+}
+
+// BreakFree releases a mouse whose struggling tore the web around it.
+func (m *Mouse) BreakFree() {
+	if m.state != mouseStuck {
+		return
+	}
+	m.setState(mouseRunning, 0)
+	m.dartTicks = 6 + m.world.Intn(8)
+	m.webCooldown = 30
+}
+
+func (m *Mouse) IsStuckInWeb() bool {
+	return m.state == mouseStuck
+}
+
+// Struggle is stronger than a butterfly's; mice tear webs faster.
+func (m *Mouse) Struggle() float64 {
+	return 0.03
+}
+
+// Leave sends a mouse that is out running for its hole at full speed. One
+// still in its hole stays there.
+func (m *Mouse) Leave() {
+	m.Lifecycle.Leave()
+	m.pauseTicks = 0
+}
+
+func (m *Mouse) SetIntensity(intensity float64) {
+	m.intensity = intensity
+}
+
+func (m *Mouse) SetLogger(log *slog.Logger) {
+	m.log = log
+}
+
+func (m *Mouse) DebugInfo() (int, int, string, bool) {
+	x, y, ok := m.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return 0, 0, "", false
+	}
+	return x, y, fmt.Sprintf("mouse %s v=%.1f", m.state, m.v*float64(m.dir)), true
+}
+
+// mousePath is the route mice run along: down the lower part of the left
+// wall, across the floor and up the right wall, measured in cells from the
+// top of the left run.
+type mousePath struct {
+	width  int
+	height int
+	wall   int
+}
+
+func newMousePath(width, height int) mousePath {
+	return mousePath{width: width, height: height, wall: max(0, min(height/3, height-1))}
+}
+
+func (p mousePath) length() float64 {
+	return float64(2*p.wall + max(0, p.width-1))
+}
+
+// holes are the places a mouse can hide: the top of each wall run and the
+// two bottom corners.
+func (p mousePath) holes() []float64 {
+	return []float64{0, float64(p.wall), float64(p.wall + max(0, p.width-1)), p.length()}
+}
+
+func (p mousePath) onWall(u float64) bool {
+	return u < float64(p.wall) || u > float64(p.wall+p.width-1)
+}
+
+func (p mousePath) at(u float64) Point {
+	i := int(math.Round(clampFloat(u, 0, p.length())))
+	floor := p.height - 1
+	if i < p.wall {
+		return Point{X: 0, Y: floor - p.wall + i}
+	}
+	i -= p.wall
+	if i < p.width-1 {
+		return Point{X: i, Y: floor}
+	}
+	i -= p.width - 1
+	return Point{X: p.width - 1, Y: floor - i}
+}
+
+func NewMouse(cfg MouseConfig) *Mouse {
+	if cfg.InitialDelayMax <= 0 {
+		cfg.InitialDelayMax = 60
+	}
+	return &Mouse{
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		Color:     cfg.Color,
+		webChance: cfg.WebChance,
+		intensity: 1.0,
+		log:       discardLogger,
+	}
+}
@@ -1,10 +1,133 @@
 package kitty
 
-import (
-	"github.com/gdamore/tcell/v3"
-)
+// PlayThingVersion is the version of the KittyPlayThing interface. It goes up
+// whenever the interface changes, so playthings kept outside this package can
+// tell which one they were written against. Version 1 was just
+// Update(tcell.Screen) and Draw(tcell.Screen); version 2 drew straight onto
+// the screen and had no Layer.
+const PlayThingVersion = 3
 
+// KittyPlayThing is anything Kitty puts on screen. Every tick Kitty calls
+// Update with the shared World, then Draw with the canvas set to the
+// plaything's layer. Ticks come at a fixed rate, so playthings move in cells
+// per tick and count time in ticks.
+//
+// Most playthings embed a Lifecycle, which provides Active and calls Spawn
+// once the plaything is due on screen. Despawn takes it off screen again:
+// the plaything calls it itself when it is done, Kitty calls it when the
+// plaything is hit, eaten or removed.
 type KittyPlayThing interface {
-	Update(s tcell.Screen)
-	Draw(s tcell.Screen)
+	Name() string
+	Active() bool
+	// Bounds is the part of the screen the plaything covers. It is empty
+	// while the plaything is not on screen.
+	Bounds() Rect
+	Spawn(w *World)
+	Despawn(reason DespawnReason)
+	Update(w *World)
+	Layer() Layer
+	Draw(c *Canvas)
+}
+
+// KittyLeaver is implemented by playthings that can wind down gracefully.
+// After Leave is called the plaything finishes what it is doing, exits the
+// screen and never respawns; HasLeft reports once it is gone.
+type KittyLeaver interface {
+	Leave()
+	HasLeft() bool
+}
+
+// KittyPrey is implemented by playthings that spider webs can catch. Kitty
+// offers a prey that flies into a web to the web with StickToWeb; caught
+// prey struggles, tearing Struggle worth of integrity off the strands
+// around it every tick, until it breaks free or the spider eats it.
+type KittyPrey interface {
+	KittyPlayThing
+	// HitPoint is the cell that touches webs, if the prey is on screen.
+	HitPoint(width, height int) (int, int, bool)
+	// StickToWeb catches the prey and reports whether it was caught; some
+	// prey gets through most webs.
+	StickToWeb() bool
+	IsStuckInWeb() bool
+	BreakFree()
+	Struggle() float64
+}
+
+// KittyTarget is implemented by playthings the laser can shoot. When the
+// laser dot reaches its HitPoint while LaserTarget reports true, Kitty fires
+// the beam and despawns it with DespawnHit.
+type KittyTarget interface {
+	KittyPlayThing
+	HitPoint(width, height int) (int, int, bool)
+	LaserTarget() bool
+}
+
+// DespawnReason says why a plaything left the screen.
+type DespawnReason int
+
+const (
+	// DespawnGone means the plaything finished on its own, usually by
+	// moving off screen.
+	DespawnGone DespawnReason = iota
+	// DespawnHit means the laser shot it.
+	DespawnHit
+	// DespawnEaten means a spider or snake ate it.
+	DespawnEaten
+	// DespawnRemoved means Kitty took it away because the scene changed.
+	DespawnRemoved
+)
+
+func (r DespawnReason) String() string {
+	switch r {
+	case DespawnGone:
+		return "gone"
+	case DespawnHit:
+		return "hit"
+	case DespawnEaten:
+		return "eaten"
+	case DespawnRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// Rect is an area of the screen in cells.
+type Rect struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+func (r Rect) Empty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Union returns the smallest rect covering both r and o.
+func (r Rect) Union(o Rect) Rect {
+	if r.Empty() {
+		return o
+	}
+	if o.Empty() {
+		return r
+	}
+	x0 := min(r.X, o.X)
+	y0 := min(r.Y, o.Y)
+	x1 := max(r.X+r.Width, o.X+o.Width)
+	y1 := max(r.Y+r.Height, o.Y+o.Height)
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// rectAround is the rect reaching rx columns and ry rows out from (x, y).
+func rectAround(x, y, rx, ry int) Rect {
+	return Rect{X: x - rx, Y: y - ry, Width: 2*rx + 1, Height: 2*ry + 1}
+}
+
+// boundsOf is the smallest rect covering every point, grown by pad cells.
+func boundsOf(points []Point, pad int) Rect {
+	var r Rect
+	for _, p := range points {
+		r = r.Union(rectAround(p.X, p.Y, pad, pad))
+	}
+	return r
 }
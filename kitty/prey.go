@@ -0,0 +1,83 @@
+package kitty
+
+import (
+	"log/slog"
+	"math"
+)
+
+// prey is what the critters a web can catch share, embedded next to
+// Lifecycle: whether a web holds them and for how long, and the effects when
+// they are lasered or eaten. The critter still picks how long it stays stuck
+// and what it does once it is free. Set name when making the critter.
+type prey struct {
+	name       string
+	stuck      bool
+	stuckTicks int
+	log        *slog.Logger
+	world      *World
+}
+
+func (p *prey) IsStuckInWeb() bool {
+	return p.stuck
+}
+
+// stick holds the prey at x, y in a web for ticks ticks.
+func (p *prey) stick(x, y float64, ticks int) {
+	p.stuck = true
+	p.stuckTicks = ticks
+	p.log.Debug(p.name+" stuck in web", "x", math.Round(x), "y", math.Round(y), "ticks", ticks)
+}
+
+// held counts down the time the prey has left in the web and lets c break
+// free once it runs out. It reports whether the prey was stuck this tick.
+func (p *prey) held(c KittyPrey) bool {
+	if !p.stuck {
+		return false
+	}
+	p.stuckTicks--
+	if p.stuckTicks <= 0 {
+		c.BreakFree()
+	}
+	return true
+}
+
+// free lets go of the prey at x, y. It reports whether the prey was stuck.
+func (p *prey) free(x, y float64) bool {
+	if !p.stuck {
+		return false
+	}
+	p.log.Debug(p.name+" broke free", "x", math.Round(x), "y", math.Round(y))
+	p.stuck = false
+	p.stuckTicks = 0
+	return true
+}
+
+// despawned shows how c left: a lasered critter bursts where it was and an
+// eaten one flashes. Either way no web holds it any more. Call it from
+// Despawn before Stop, while c is still on screen.
+func (p *prey) despawned(c KittyPrey, reason DespawnReason) {
+	x, y, ok := c.HitPoint(math.MaxInt32, math.MaxInt32)
+	switch reason {
+	case DespawnHit:
+		p.log.Debug(p.name+" lasered", "x", x, "y", y)
+		if ok {
+			p.world.Effects.Emit(fxBurst, float64(x), float64(y))
+		}
+	case DespawnEaten:
+		p.log.Debug(p.name+" eaten", "x", x, "y", y)
+		if ok {
+			p.world.Effects.Emit(fxCaught, float64(x), float64(y))
+		}
+	}
+	p.stuck = false
+	p.stuckTicks = 0
+}
+
+// cellAt is the cell x, y falls in, if it is on a width by height screen.
+func cellAt(x, y float64, width, height int) (int, int, bool) {
+	cx, cy := int(math.Round(x)), int(math.Round(y))
+	if cx < 0 || cy < 0 || cx >= width || cy >= height {
+		return 0, 0, false
+	}
+	return cx, cy, true
+}
@@ -0,0 +1,36 @@
+package kitty
+
+import "testing"
+
+func TestPreyStaysStuckUntilFree(t *testing.T) {
+	w := NewWorld(1)
+	w.Width, w.Height = 80, 24
+	for _, p := range []KittyPrey{
+		NewButterfly(DefaultButterflyConfig()),
+		NewFly(DefaultFlyConfig()),
+		NewFirefly(DefaultFireflyConfig()),
+	} {
+		t.Run(p.Name(), func(t *testing.T) {
+			p.Spawn(w)
+			if !p.StickToWeb() || !p.IsStuckInWeb() {
+				t.Fatal("did not stick to the web")
+			}
+			ticks := 0
+			for ; p.IsStuckInWeb() && ticks < 1000; ticks++ {
+				p.Update(w)
+			}
+			if p.IsStuckInWeb() {
+				t.Fatal("never broke free")
+			}
+			if ticks < 60 {
+				t.Errorf("broke free after %d ticks, want at least 60", ticks)
+			}
+
+			p.StickToWeb()
+			p.Despawn(DespawnEaten)
+			if p.IsStuckInWeb() || p.Active() {
+				t.Error("eaten prey is still on screen in the web")
+			}
+		})
+	}
+}
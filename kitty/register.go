@@ -0,0 +1,192 @@
+package kitty
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Factory makes one plaything of a registered type, set up from the options
+// it was given on the command line. It returns an error for options it
+// cannot use.
+type Factory func(opts Options) (KittyPlayThing, error)
+
+// registry holds the plaything types other packages add with Register. Like
+// database/sql drivers, types register themselves from init, before any
+// Kitty is made.
+var registry = struct {
+	sync.RWMutex
+	factories map[string]Factory
+}{factories: map[string]Factory{}}
+
+// Register adds a plaything type made by factory under name, so a program
+// only has to import the package that defines it. Every Kitty then puts one
+// on screen, unless KittyConfig.Extensions says otherwise. Register panics if
+// the name is empty, taken by a built-in type or registered twice.
+func Register(name string, factory Factory) {
+	registry.Lock()
+	defer registry.Unlock()
+	if name == "" || strings.ContainsAny(name, ":;,= ") {
+		panic(fmt.Sprintf("kitty: Register: bad name %q", name))
+	}
+	if factory == nil {
+		panic("kitty: Register: nil factory for " + name)
+	}
+	if _, ok := drawRank[name]; ok {
+		panic("kitty: Register: " + name + " is a built-in plaything")
+	}
+	if _, ok := registry.factories[name]; ok {
+		panic("kitty: Register: " + name + " registered twice")
+	}
+	registry.factories[name] = factory
+}
+
+// Registered lists the registered plaything types, sorted.
+func Registered() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.factories))
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func registeredFactory(name string) (Factory, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	f, ok := registry.factories[name]
+	return f, ok
+}
+
+// ExtensionConfig sets up one registered plaything type: Count is how many
+// Kitty puts on screen and Options are handed to its factory.
+type ExtensionConfig struct {
+	Name    string
+	Count   int
+	Options map[string]string
+}
+
+// ParseExtension parses an extension as given on the command line, a name
+// optionally followed by comma separated options, e.g.
+// "comet:count=2,speed=1.5". The count option sets Count, which defaults
+// to 1.
+func ParseExtension(spec string) (ExtensionConfig, error) {
+	name, rest, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	cfg := ExtensionConfig{Name: strings.TrimSpace(name), Count: 1, Options: map[string]string{}}
+	if cfg.Name == "" {
+		return ExtensionConfig{}, fmt.Errorf("extension %q has no name", spec)
+	}
+	for _, pair := range strings.Split(rest, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return ExtensionConfig{}, fmt.Errorf("%s: want key=value, not %q", cfg.Name, pair)
+		}
+		cfg.Options[key] = strings.TrimSpace(value)
+	}
+	if v, ok := cfg.Options["count"]; ok {
+		count, err := strconv.Atoi(v)
+		if err != nil {
+			return ExtensionConfig{}, fmt.Errorf("%s: count must be a whole number, not %q", cfg.Name, v)
+		}
+		cfg.Count = count
+		delete(cfg.Options, "count")
+	}
+	return cfg, nil
+}
+
+// Options are the settings for one registered plaything type, given as
+// key=value pairs. Count is how many Kitty puts on screen.
+type Options struct {
+	Name   string
+	Count  int
+	values map[string]string
+}
+
+// String returns the option called key, or def if it was not given.
+func (o Options) String(key, def string) string {
+	if v, ok := o.values[key]; ok {
+		return v
+	}
+	return def
+}
+
+// Int returns the option called key as a whole number, or def if it was not
+// given.
+func (o Options) Int(key string, def int) (int, error) {
+	v, ok := o.values[key]
+	if !ok {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s must be a whole number, not %q", o.Name, key, v)
+	}
+	return n, nil
+}
+
+// Float returns the option called key as a number, or def if it was not
+// given.
+func (o Options) Float(key string, def float64) (float64, error) {
+	v, ok := o.values[key]
+	if !ok {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s must be a number, not %q", o.Name, key, v)
+	}
+	return f, nil
+}
+
+// extension is a registered type with the options it runs with.
+type extension struct {
+	factory Factory
+	opts    Options
+}
+
+// loadExtensions checks every configured type is registered and can be made
+// with its options. Registered types the configs leave out run once with no
+// options.
+func loadExtensions(configs []ExtensionConfig) ([]extension, error) {
+	given := map[string]ExtensionConfig{}
+	for _, cfg := range configs {
+		if _, ok := registeredFactory(cfg.Name); !ok {
+			registered := Registered()
+			if len(registered) == 0 {
+				return nil, fmt.Errorf("unknown plaything %q: none are registered", cfg.Name)
+			}
+			return nil, fmt.Errorf("unknown plaything %q: want one of %s", cfg.Name, strings.Join(registered, ", "))
+		}
+		if _, ok := given[cfg.Name]; ok {
+			return nil, fmt.Errorf("plaything %q is configured twice", cfg.Name)
+		}
+		if cfg.Count < 0 {
+			return nil, fmt.Errorf("%s: count must not be negative", cfg.Name)
+		}
+		given[cfg.Name] = cfg
+	}
+
+	var out []extension
+	for _, name := range Registered() {
+		factory, _ := registeredFactory(name)
+		opts := Options{Name: name, Count: 1}
+		if cfg, ok := given[name]; ok {
+			opts.Count = cfg.Count
+			opts.values = cfg.Options
+		}
+		// make one now so bad options fail before the screen starts
+		if _, err := factory(opts); err != nil {
+			return nil, err
+		}
+		out = append(out, extension{factory: factory, opts: opts})
+	}
+	return out, nil
+}
@@ -0,0 +1,147 @@
+package kitty
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// target is a plaything registered by a test: it sits still at the x, y
+// option until the laser shoots it.
+type target struct {
+	Lifecycle
+	x, y int
+}
+
+func newTarget(opts Options) (KittyPlayThing, error) {
+	x, err := opts.Int("x", 0)
+	if err != nil {
+		return nil, err
+	}
+	y, err := opts.Int("y", 0)
+	if err != nil {
+		return nil, err
+	}
+	return &target{x: x, y: y}, nil
+}
+
+func (t *target) Name() string                 { return "target" }
+func (t *target) Layer() Layer                 { return LayerCritters }
+func (t *target) Spawn(w *World)               { t.Start() }
+func (t *target) Despawn(reason DespawnReason) { t.Stop(100) }
+func (t *target) Update(w *World)              { t.Tick(t, w) }
+func (t *target) Draw(c *Canvas)               { c.Set(t.x, t.y, 'o', 0) }
+func (t *target) LaserTarget() bool            { return t.Active() }
+
+func (t *target) Bounds() Rect {
+	if !t.Active() {
+		return Rect{}
+	}
+	return Rect{X: t.x, Y: t.y, Width: 1, Height: 1}
+}
+
+func (t *target) HitPoint(width, height int) (int, int, bool) {
+	return t.x, t.y, t.Active() && t.x < width && t.y < height
+}
+
+// registerForTest registers factory under name until the test ends, so the
+// type does not turn up in other tests' Kittys.
+func registerForTest(t *testing.T, name string, factory Factory) {
+	t.Helper()
+	Register(name, factory)
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		delete(registry.factories, name)
+	})
+}
+
+func TestRegisteredTargetIsLasered(t *testing.T) {
+	registerForTest(t, "target", newTarget)
+	ext, err := ParseExtension("target:count=2,x=10,y=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ext.Count != 2 || ext.Options["x"] != "10" {
+		t.Fatalf("ParseExtension = %+v", ext)
+	}
+
+	cfg := KittyConfig{Extensions: []ExtensionConfig{ext}}
+	k := newTestKitty(t, cfg)
+	k.spawnPlayThings()
+	if len(k.objects) != 2 {
+		t.Fatalf("spawned %d playthings, want the 2 targets", len(k.objects))
+	}
+	hit := k.objects[0].(*target)
+	if hit.x != 10 || hit.y != 5 {
+		t.Fatalf("target at %d,%d, want the 10,5 from its options", hit.x, hit.y)
+	}
+	missed := k.objects[1].(*target)
+	missed.x = 40
+	hit.Spawn(k.world)
+	missed.Spawn(k.world)
+
+	laser := NewLaserPointer(DefaultLaserConfig())
+	laser.Spawn(k.world)
+	laser.x, laser.y = 11, 5
+	k.objects = append(k.objects, laser)
+
+	k.handleLaserHits()
+	if hit.Active() {
+		t.Error("target under the laser was not despawned")
+	}
+	if !missed.Active() {
+		t.Error("target away from the laser was despawned")
+	}
+	if !laser.Firing() {
+		t.Error("laser did not fire at the target")
+	}
+	if n := k.Stats().Lasered["target"]; n != 1 {
+		t.Errorf("Lasered[target] = %d, want 1", n)
+	}
+}
+
+func TestNewRejectsBadExtensionOptions(t *testing.T) {
+	registerForTest(t, "target", newTarget)
+	for spec, want := range map[string]string{
+		"target:x=left":   "x must be a whole number",
+		"nothing":         "unknown plaything",
+		"target:count=-1": "count must not be negative",
+	} {
+		ext, err := ParseExtension(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = newKitty(KittyConfig{Extensions: []ExtensionConfig{ext}}, newMockScreen)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("New with extension %q: err = %v, want %q", spec, err, want)
+		}
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	registerForTest(t, "target", newTarget)
+	for _, tc := range []struct {
+		name    string
+		factory Factory
+		want    string
+	}{
+		{"", newTarget, "bad name"},
+		{"snake", newTarget, "built-in"},
+		{"target", newTarget, "registered twice"},
+		{"other", nil, "nil factory"},
+	} {
+		t.Run(fmt.Sprintf("%q", tc.name), func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Fatalf("Register(%q) did not panic", tc.name)
+				}
+				if msg := fmt.Sprint(r); !strings.Contains(msg, tc.want) {
+					t.Errorf("Register(%q) panicked with %q, want %q", tc.name, msg, tc.want)
+				}
+			}()
+			Register(tc.name, tc.factory)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package kitty
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Scene is a curated set of playthings and a backdrop, so a cat can be
+// entertained without tuning counts by hand. A scene is plain data; Config
+// expands it over the defaults.
+type Scene struct {
+	Name        string
+	Description string
+	// Counts is how many of each plaything type play, by type name, e.g.
+	// "butterfly". Types left out play none.
+	Counts map[string]int
+	// Background replaces the default backdrop field by field; zero fields
+	// keep the default.
+	Background BackgroundConfig
+	// FishSize, LaserSpeed, WebSpokes and WebRings tune the playthings;
+	// zero keeps the default.
+	FishSize   int
+	LaserSpeed float64
+	WebSpokes  int
+	WebRings   int
+}
+
+// Config expands the scene into a full config: the defaults, with only the
+// playthings the scene counts turned on and its settings on top.
+func (s Scene) Config() KittyConfig {
+	cfg := DefaultKittyConfig()
+	for name, count := range cfg.counts() {
+		*count = s.Counts[name]
+	}
+	bg := s.Background
+	if bg.Color != 0 {
+		cfg.Background.Color = bg.Color
+	}
+	if bg.GroundColor != 0 {
+		cfg.Background.GroundColor = bg.GroundColor
+	}
+	if bg.GroundRows != 0 {
+		cfg.Background.GroundRows = bg.GroundRows
+	}
+	if bg.Palette != "" {
+		cfg.Background.Palette = bg.Palette
+	}
+	if s.FishSize != 0 {
+		cfg.FishConfig.Size = s.FishSize
+	}
+	if s.LaserSpeed != 0 {
+		cfg.LaserConfig.Speed = s.LaserSpeed
+	}
+	if s.WebSpokes != 0 {
+		cfg.SpiderConfig.WebSpokes = s.WebSpokes
+	}
+	if s.WebRings != 0 {
+		cfg.SpiderConfig.WebRings = s.WebRings
+	}
+	return cfg
+}
+
+// counts points at the count of each built-in plaything type, by type name.
+func (c *KittyConfig) counts() map[string]*int {
+	return map[string]*int{
+		"snake":     &c.SnakeCount,
+		"string":    &c.SwayStringCount,
+		"butterfly": &c.ButterflyCount,
+		"mouse":     &c.MouseCount,
+		"fish":      &c.FishCount,
+		"wand":      &c.WandCount,
+		"yarn":      &c.YarnCount,
+		"fly":       &c.FlyCount,
+		"firefly":   &c.FireflyCount,
+		"bird":      &c.BirdCount,
+		"laser":     &c.LaserCount,
+		"spider":    &c.SpiderCount,
+	}
+}
+
+var scenes = []Scene{
+	{
+		Name:        "garden",
+		Description: "Butterflies, swaying strings and birds over a band of green grass",
+		Counts:      map[string]int{"butterfly": 4, "string": 3, "bird": 2, "fly": 1, "laser": 1},
+		Background:  BackgroundConfig{GroundColor: color.Green, GroundRows: 2},
+	},
+	{
+		Name:        "aquarium",
+		Description: "Schools of fish trailing bubbles through blue water over sand",
+		Counts:      map[string]int{"fish": 3, "laser": 1},
+		Background:  BackgroundConfig{Color: color.Navy, GroundColor: color.Tan, GroundRows: 1},
+		FishSize:    8,
+	},
+	{
+		Name:        "night",
+		Description: "Fireflies and a slow laser on a dark screen",
+		Counts:      map[string]int{"firefly": 6, "laser": 1},
+		Background:  BackgroundConfig{Color: color.Black, Palette: "dark"},
+		LaserSpeed:  0.5,
+	},
+	{
+		Name:        "attic",
+		Description: "Spiders spinning dense webs in a dim attic, with flies and a mouse to catch",
+		Counts:      map[string]int{"spider": 4, "fly": 3, "mouse": 1, "laser": 1},
+		Background:  BackgroundConfig{Palette: "dark"},
+		WebSpokes:   14,
+		WebRings:    8,
+	},
+}
+
+// Scenes lists the preset scenes.
+func Scenes() []Scene {
+	return append([]Scene(nil), scenes...)
+}
+
+// SceneByName finds the preset scene called name.
+func SceneByName(name string) (Scene, error) {
+	names := make([]string, len(scenes))
+	for i, s := range scenes {
+		if s.Name == name {
+			return s, nil
+		}
+		names[i] = s.Name
+	}
+	return Scene{}, fmt.Errorf("unknown scene %q (want %s)", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,47 @@
+package kitty
+
+import (
+	"testing"
+)
+
+func TestScenesExpandToValidConfigs(t *testing.T) {
+	defaults := DefaultKittyConfig()
+	counts := defaults.counts()
+	for _, sc := range Scenes() {
+		t.Run(sc.Name, func(t *testing.T) {
+			for name := range sc.Counts {
+				if counts[name] == nil {
+					t.Errorf("scene counts unknown plaything %q", name)
+				}
+			}
+			cfg := sc.Config()
+			k := newTestKitty(t, cfg)
+			k.spawnPlayThings()
+			if len(k.objects) == 0 {
+				t.Fatal("scene has no playthings")
+			}
+			for _, o := range k.objects {
+				if o.Name() == "snake" {
+					t.Error("scene plays the default snakes it does not list")
+				}
+			}
+			for i := 0; i < 100; i++ {
+				k.step()
+				k.draw()
+			}
+		})
+	}
+}
+
+func TestSceneByName(t *testing.T) {
+	sc, err := SceneByName("night")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg := sc.Config(); cfg.FireflyCount == 0 || cfg.Background.Palette != "dark" {
+		t.Errorf("night scene config = %+v", cfg)
+	}
+	if _, err := SceneByName("beach"); err == nil {
+		t.Error("SceneByName found an unknown scene")
+	}
+}
@@ -0,0 +1,431 @@
+package kitty
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// Script is a Starlark file that describes a critter. It defines
+//
+//	def init(self, kitty):    # optional, when the critter appears
+//	def update(self, kitty):  # every tick
+//	def draw(self, kitty):    # every frame, after update
+//
+// where self is a dict the critter keeps its state in and kitty is the API
+// the script may use. The file may also set name, layer, color and
+// initial_delay_max. Scripts cannot reach files, the network or the rest of
+// the program.
+type Script struct {
+	Path            string
+	Name            string
+	Layer           Layer
+	Color           tcell.Color
+	InitialDelayMax int
+
+	init   *starlark.Function
+	update *starlark.Function
+	draw   *starlark.Function
+}
+
+// scriptSteps caps the Starlark steps one callback may take, so a runaway
+// loop fails the critter instead of hanging the screen.
+const scriptSteps = 1_000_000
+
+// LoadScript runs a script file once to pick up its callbacks and
+// settings. Errors name the file.
+func LoadScript(path string) (*Script, error) {
+	thread := &starlark.Thread{Name: path, Print: func(*starlark.Thread, string) {}}
+	thread.SetMaxExecutionSteps(scriptSteps)
+	opts := &syntax.FileOptions{While: true, Set: true}
+	globals, err := starlark.ExecFileOptions(opts, thread, path, nil, nil)
+	if err != nil {
+		var evalErr *starlark.EvalError
+		if errors.As(err, &evalErr) {
+			return nil, errors.New(evalErr.Backtrace())
+		}
+		return nil, err
+	}
+	s := &Script{
+		Path:            path,
+		Name:            strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Layer:           LayerCritters,
+		InitialDelayMax: 60,
+	}
+	if v, ok := globals["name"]; ok {
+		name, ok := starlark.AsString(v)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s: name must be a non-empty string", path)
+		}
+		s.Name = name
+	}
+	if v, ok := globals["layer"]; ok {
+		name, _ := starlark.AsString(v)
+		layer, ok := parseLayer(name)
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown layer %s", path, v)
+		}
+		s.Layer = layer
+	}
+	if v, ok := globals["color"]; ok {
+		name, _ := starlark.AsString(v)
+		col := color.GetColor(strings.ToLower(name))
+		if col == color.Default {
+			return nil, fmt.Errorf("%s: unknown color %s", path, v)
+		}
+		s.Color = col
+	}
+	if v, ok := globals["initial_delay_max"]; ok {
+		n, err := starlark.AsInt32(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s: initial_delay_max must be a whole number of 0 or more", path)
+		}
+		s.InitialDelayMax = n
+	}
+	for _, cb := range []struct {
+		name     string
+		fn       **starlark.Function
+		required bool
+	}{
+		{"init", &s.init, false},
+		{"update", &s.update, true},
+		{"draw", &s.draw, true},
+	} {
+		v, ok := globals[cb.name]
+		if !ok {
+			if cb.required {
+				return nil, fmt.Errorf("%s: no %s(self, kitty) function", path, cb.name)
+			}
+			continue
+		}
+		fn, ok := v.(*starlark.Function)
+		if !ok || fn.NumParams() != 2 {
+			return nil, fmt.Errorf("%s: %s must be a function of (self, kitty)", path, cb.name)
+		}
+		*cb.fn = fn
+	}
+	return s, nil
+}
+
+// NewCritter makes a plaything run by the script.
+func (s *Script) NewCritter() *ScriptCritter {
+	c := &ScriptCritter{
+		Lifecycle: Lifecycle{InitialDelayMax: s.InitialDelayMax},
+		script:    s,
+		intensity: 1.0,
+		log:       discardLogger,
+	}
+	c.api = newScriptAPI(c)
+	c.thread = &starlark.Thread{
+		Name: s.Path,
+		Print: func(_ *starlark.Thread, msg string) {
+			c.log.Debug("script print", "script", s.Path, "msg", msg)
+		},
+	}
+	return c
+}
+
+// ScriptCritter is a plaything whose behavior and looks come from a Script.
+// A script error disables the critter for the rest of the session; it is
+// logged and the other playthings carry on.
+type ScriptCritter struct {
+	Lifecycle
+
+	script    *Script
+	api       *scriptAPI
+	thread    *starlark.Thread
+	self      *starlark.Dict
+	ticks     int
+	bounds    Rect
+	disabled  bool
+	intensity float64
+	log       *slog.Logger
+	world     *World
+}
+
+func (s *ScriptCritter) Name() string {
+	return s.script.Name
+}
+
+func (s *ScriptCritter) Layer() Layer {
+	return s.script.Layer
+}
+
+func (s *ScriptCritter) Update(w *World) {
+	s.world = w
+	if s.disabled || !s.Tick(s, w) {
+		return
+	}
+	s.ticks++
+	s.call(s.script.update)
+}
+
+func (s *ScriptCritter) Spawn(w *World) {
+	s.world = w
+	s.Start()
+	s.ticks = 0
+	s.bounds = Rect{}
+	s.self = starlark.NewDict(8)
+	if s.script.init != nil {
+		s.call(s.script.init)
+	}
+}
+
+func (s *ScriptCritter) Despawn(reason DespawnReason) {
+	wait := 0
+	if reason != DespawnRemoved && s.world != nil {
+		wait = calmWait(60+s.world.Intn(120), s.intensity)
+	}
+	s.Stop(wait)
+}
+
+// Bounds covers the cells the script drew last frame.
+func (s *ScriptCritter) Bounds() Rect {
+	if !s.Active() {
+		return Rect{}
+	}
+	return s.bounds
+}
+
+func (s *ScriptCritter) Draw(c *Canvas) {
+	if !s.Active() || s.disabled {
+		return
+	}
+	s.bounds = Rect{}
+	s.api.canvas = c
+	s.call(s.script.draw)
+	s.api.canvas = nil
+}
+
+// call runs a callback with the critter's state and the API, disabling the
+// critter if it fails.
+func (s *ScriptCritter) call(fn *starlark.Function) {
+	s.thread.SetMaxExecutionSteps(s.thread.ExecutionSteps() + scriptSteps)
+	_, err := starlark.Call(s.thread, fn, starlark.Tuple{s.self, s.api}, nil)
+	if err == nil {
+		return
+	}
+	msg := err.Error()
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		msg = evalErr.Backtrace()
+	}
+	s.log.Error("script disabled", "script", s.script.Path, "name", s.script.Name, "err", msg)
+	s.disabled = true
+	s.Leave()
+	s.Stop(0)
+}
+
+func (s *ScriptCritter) SetIntensity(intensity float64) {
+	s.intensity = intensity
+}
+
+func (s *ScriptCritter) SetLogger(log *slog.Logger) {
+	s.log = log
+}
+
+func (s *ScriptCritter) DebugInfo() (int, int, string, bool) {
+	if !s.Active() || s.bounds.Empty() {
+		return 0, 0, "", false
+	}
+	x := s.bounds.X + s.bounds.Width/2
+	y := s.bounds.Y + s.bounds.Height/2
+	return x, y, fmt.Sprintf("%s tick=%d", s.script.Name, s.ticks), true
+}
+
+// scriptEffects are the effects a script can emit, by name.
+var scriptEffects = map[string]Effect{
+	"burst":     fxBurst,
+	"caught":    fxCaught,
+	"spark":     fxSpark,
+	"wing_dust": fxWingDust,
+	"dust":      fxSlitherDust,
+	"web_snap":  fxWebSnap,
+	"bubble":    fxBubble,
+}
+
+// scriptAPI is the kitty value scripts get. Its attributes read the world
+// as it is at the time of the call; set only works while drawing.
+type scriptAPI struct {
+	critter  *ScriptCritter
+	canvas   *Canvas
+	builtins map[string]*starlark.Builtin
+}
+
+func newScriptAPI(c *ScriptCritter) *scriptAPI {
+	a := &scriptAPI{critter: c}
+	a.builtins = map[string]*starlark.Builtin{
+		"random":  starlark.NewBuiltin("random", a.random),
+		"randint": starlark.NewBuiltin("randint", a.randint),
+		"set":     starlark.NewBuiltin("set", a.set),
+		"nearby":  starlark.NewBuiltin("nearby", a.nearby),
+		"emit":    starlark.NewBuiltin("emit", a.emit),
+		"despawn": starlark.NewBuiltin("despawn", a.despawn),
+	}
+	return a
+}
+
+func (a *scriptAPI) String() string        { return "kitty" }
+func (a *scriptAPI) Type() string          { return "kitty" }
+func (a *scriptAPI) Freeze()               {}
+func (a *scriptAPI) Truth() starlark.Bool  { return starlark.True }
+func (a *scriptAPI) Hash() (uint32, error) { return 0, errors.New("unhashable type: kitty") }
+
+func (a *scriptAPI) Attr(name string) (starlark.Value, error) {
+	c := a.critter
+	switch name {
+	case "width":
+		return starlark.MakeInt(c.world.Width), nil
+	case "height":
+		return starlark.MakeInt(c.world.Height), nil
+	case "tick":
+		return starlark.MakeInt(c.ticks), nil
+	case "leaving":
+		return starlark.Bool(c.Leaving()), nil
+	case "intensity":
+		return starlark.Float(c.intensity), nil
+	}
+	if b, ok := a.builtins[name]; ok {
+		return b, nil
+	}
+	return nil, nil
+}
+
+func (a *scriptAPI) AttrNames() []string {
+	names := []string{"width", "height", "tick", "leaving", "intensity"}
+	for name := range a.builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// random() returns a float in [0, 1).
+func (a *scriptAPI) random(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	return starlark.Float(a.critter.world.Float64()), nil
+}
+
+// randint(lo, hi) returns a whole number from lo to hi, both included.
+func (a *scriptAPI) randint(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var lo, hi int
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "lo", &lo, "hi", &hi); err != nil {
+		return nil, err
+	}
+	if hi < lo {
+		return nil, fmt.Errorf("hi %d is less than lo %d", hi, lo)
+	}
+	return starlark.MakeInt(lo + a.critter.world.Intn(hi-lo+1)), nil
+}
+
+// set(x, y, char, color=None) draws one cell, in the script's color unless
+// another is named.
+func (a *scriptAPI) set(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x, y starlark.Value
+	var char string
+	var colorName starlark.Value = starlark.None
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "x", &x, "y", &y, "char", &char, "color?", &colorName); err != nil {
+		return nil, err
+	}
+	if a.canvas == nil {
+		return nil, errors.New("only works in draw")
+	}
+	px, py, err := scriptPoint(x, y)
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(char)
+	if len(runes) == 0 {
+		return nil, errors.New("empty char")
+	}
+	fg := a.critter.script.Color
+	if fg == tcell.ColorDefault || fg == 0 {
+		fg = color.White
+	}
+	if colorName != starlark.None {
+		name, _ := starlark.AsString(colorName)
+		if fg = color.GetColor(strings.ToLower(name)); fg == color.Default {
+			return nil, fmt.Errorf("unknown color %s", colorName)
+		}
+	}
+	a.canvas.Set(px, py, runes[0], fg)
+	a.critter.bounds = a.critter.bounds.Union(rectAround(px, py, 0, 0))
+	return starlark.None, nil
+}
+
+// nearby(x, y, radius) lists what World.Nearby sees, as structs with kind,
+// x and y.
+func (a *scriptAPI) nearby(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x, y, radius starlark.Value
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "x", &x, "y", &y, "radius", &radius); err != nil {
+		return nil, err
+	}
+	fx, okX := starlark.AsFloat(x)
+	fy, okY := starlark.AsFloat(y)
+	r, okR := starlark.AsFloat(radius)
+	if !okX || !okY || !okR {
+		return nil, errors.New("x, y and radius must be numbers")
+	}
+	found := a.critter.world.Nearby(fx, fy, r)
+	out := make([]starlark.Value, 0, len(found))
+	for _, n := range found {
+		out = append(out, starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+			"kind": starlark.String(n.Kind),
+			"x":    starlark.MakeInt(n.Point.X),
+			"y":    starlark.MakeInt(n.Point.Y),
+		}))
+	}
+	return starlark.NewList(out), nil
+}
+
+// emit(effect, x, y) sets off a particle effect: burst, caught, spark,
+// wing_dust, dust, web_snap or bubble.
+func (a *scriptAPI) emit(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var x, y starlark.Value
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "effect", &name, "x", &x, "y", &y); err != nil {
+		return nil, err
+	}
+	fx, ok := scriptEffects[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown effect %q", name)
+	}
+	px, py, err := scriptPoint(x, y)
+	if err != nil {
+		return nil, err
+	}
+	a.critter.world.Effects.Emit(fx, float64(px), float64(py))
+	return starlark.None, nil
+}
+
+// despawn() takes the critter off screen; it comes back after a while
+// unless the session is winding down.
+func (a *scriptAPI) despawn(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	a.critter.Despawn(DespawnGone)
+	return starlark.None, nil
+}
+
+// scriptPoint rounds a script's x and y, ints or floats, to a cell.
+func scriptPoint(x, y starlark.Value) (int, int, error) {
+	fx, okX := starlark.AsFloat(x)
+	fy, okY := starlark.AsFloat(y)
+	if !okX || !okY {
+		return 0, 0, errors.New("x and y must be numbers")
+	}
+	return int(math.Round(fx)), int(math.Round(fy)), nil
+}
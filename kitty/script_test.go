@@ -0,0 +1,152 @@
+package kitty
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeScript saves src as a script in a temporary directory.
+func writeScript(t *testing.T, name, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// scriptKitty plays the script at path next to a sway string, logging to
+// the returned buffer. It returns the script's critter and the string.
+func scriptKitty(t *testing.T, path string) (*Kitty, *ScriptCritter, *SwayString, *bytes.Buffer) {
+	t.Helper()
+	var log bytes.Buffer
+	cfg := KittyConfig{
+		SwayStringCount: 1,
+		Scripts:         []string{path},
+		Logger:          slog.New(slog.NewTextHandler(&log, nil)),
+	}
+	k := newTestKitty(t, cfg)
+	k.spawnForPhase(SessionPlay)
+	var critter *ScriptCritter
+	var str *SwayString
+	for _, o := range k.objects {
+		switch o := o.(type) {
+		case *ScriptCritter:
+			critter = o
+		case *SwayString:
+			str = o
+		}
+	}
+	if critter == nil || str == nil {
+		t.Fatalf("spawned %d playthings without the script and the string", len(k.objects))
+	}
+	return k, critter, str, &log
+}
+
+const scriptHeader = `
+initial_delay_max = 0
+
+def draw(self, kitty):
+    kitty.set(1, 1, "x")
+`
+
+func TestScriptErrorDisablesCritter(t *testing.T) {
+	path := writeScript(t, "broken.star", scriptHeader+`
+def update(self, kitty):
+    if kitty.tick == 3:
+        fail("boom")
+`)
+	k, critter, str, log := scriptKitty(t, path)
+	for i := 0; i < 200; i++ {
+		k.step()
+		k.draw()
+	}
+	if !critter.disabled || critter.Active() {
+		t.Errorf("failed script is disabled=%v active=%v, want disabled and off screen", critter.disabled, critter.Active())
+	}
+	if critter.ticks != 3 {
+		t.Errorf("failed script ran %d ticks, want it stopped at 3", critter.ticks)
+	}
+	if !strings.Contains(log.String(), "script disabled") || !strings.Contains(log.String(), "boom") {
+		t.Errorf("log does not report the script error:\n%s", log.String())
+	}
+	if str.Spawns() == 0 {
+		t.Error("sway string never spawned after the script failed")
+	}
+}
+
+func TestScriptRunawayLoopIsCutOff(t *testing.T) {
+	path := writeScript(t, "loop.star", scriptHeader+`
+def update(self, kitty):
+    while True:
+        pass
+`)
+	k, critter, _, log := scriptKitty(t, path)
+	for i := 0; i < 3; i++ {
+		k.step()
+	}
+	if !critter.disabled {
+		t.Fatal("script stuck in a loop was not disabled")
+	}
+	if !strings.Contains(log.String(), "too many steps") {
+		t.Errorf("log does not report the step cap:\n%s", log.String())
+	}
+}
+
+func TestLoadScriptErrors(t *testing.T) {
+	update := "\ndef update(self, kitty):\n    pass\n"
+	draw := "\ndef draw(self, kitty):\n    pass\n"
+	for _, tc := range []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"no update", draw, "no update(self, kitty) function"},
+		{"no draw", update, "no draw(self, kitty) function"},
+		{"wrong arity", draw + "\ndef update(self):\n    pass\n", "update must be a function of (self, kitty)"},
+		{"update not a function", draw + "update = 3\n", "update must be a function of (self, kitty)"},
+		{"bad layer", update + draw + "layer = \"sky\"\n", "unknown layer \"sky\""},
+		{"bad color", update + draw + "color = \"nope\"\n", "unknown color \"nope\""},
+		{"bad delay", update + draw + "initial_delay_max = -1\n", "initial_delay_max must be"},
+		{"empty name", update + draw + "name = \"\"\n", "name must be a non-empty string"},
+		{"syntax error", "def update(self, kitty)\n", "got newline"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeScript(t, "bad.star", tc.src)
+			_, err := LoadScript(path)
+			if err == nil {
+				t.Fatal("LoadScript succeeded")
+			}
+			if !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q naming the file", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestGhostScriptPlays(t *testing.T) {
+	s, err := LoadScript("../examples/scripts/ghost.star")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "ghost" || s.Layer != LayerCritters || s.init == nil {
+		t.Fatalf("ghost loaded as %+v", s)
+	}
+	k, critter, _, log := scriptKitty(t, s.Path)
+	drawn := false
+	for i := 0; i < 300; i++ {
+		k.step()
+		k.draw()
+		drawn = drawn || !critter.Bounds().Empty()
+	}
+	if critter.disabled {
+		t.Fatalf("ghost was disabled:\n%s", log.String())
+	}
+	if !drawn {
+		t.Error("ghost never drew itself")
+	}
+}
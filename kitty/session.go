@@ -0,0 +1,156 @@
+package kitty
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type SessionPhase int
+
+const (
+	SessionPlay SessionPhase = iota
+	SessionRest
+	SessionOver
+)
+
+func (p SessionPhase) String() string {
+	switch p {
+	case SessionPlay:
+		return "play"
+	case SessionRest:
+		return "rest"
+	case SessionOver:
+		return "over"
+	}
+	return "unknown"
+}
+
+// PlayWindow is a daily time-of-day range, stored as offsets from midnight.
+// A window whose End is before its Start wraps past midnight.
+type PlayWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+func (w PlayWindow) contains(sinceMidnight time.Duration) bool {
+	if w.Start <= w.End {
+		return sinceMidnight >= w.Start && sinceMidnight < w.End
+	}
+	return sinceMidnight >= w.Start || sinceMidnight < w.End
+}
+
+// ParseSchedule parses a comma separated list of "HH:MM-HH:MM" windows,
+// e.g. "08:00-08:20,18:00-18:30".
+func ParseSchedule(spec string) ([]PlayWindow, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	var windows []PlayWindow
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		startStr, endStr, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("schedule window %q: expected HH:MM-HH:MM", part)
+		}
+		start, err := parseClock(startStr)
+		if err != nil {
+			return nil, fmt.Errorf("schedule window %q: %w", part, err)
+		}
+		end, err := parseClock(endStr)
+		if err != nil {
+			return nil, fmt.Errorf("schedule window %q: %w", part, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("schedule window %q: start and end are equal", part)
+		}
+		windows = append(windows, PlayWindow{Start: start, End: end})
+	}
+	return windows, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// sessionClock decides whether the cat should be playing or resting.
+//
+// Precedence: an expired Duration ends the session, then a Schedule picks
+// play windows by time of day, then Play/Rest cycles are counted from the
+// start of the session. A Schedule ends the session when its final window
+// does. With nothing configured the session plays forever.
+type sessionClock struct {
+	cfg     SessionConfig
+	windows []PlayWindow
+	start   time.Time
+	// end is when the final window of the schedule closes, if there is one.
+	end time.Time
+}
+
+func newSessionClock(cfg SessionConfig, start time.Time) (*sessionClock, error) {
+	windows, err := ParseSchedule(cfg.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Duration < 0 || cfg.Play < 0 || cfg.Rest < 0 {
+		return nil, fmt.Errorf("session durations must not be negative")
+	}
+	if (cfg.Play > 0) != (cfg.Rest > 0) {
+		return nil, fmt.Errorf("play and rest periods must be set together")
+	}
+	c := &sessionClock{cfg: cfg, windows: windows, start: start}
+	if len(windows) > 0 {
+		c.end = scheduleEnd(windows, start)
+	}
+	return c, nil
+}
+
+// scheduleEnd is the first time after start that the final window of the
+// day, the one that starts last, closes. A session started after it has
+// closed for the day plays through the next day's windows.
+func scheduleEnd(windows []PlayWindow, start time.Time) time.Time {
+	final := windows[0]
+	for _, w := range windows[1:] {
+		if w.Start > final.Start {
+			final = w
+		}
+	}
+	midnight := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	end := midnight.Add(final.End)
+	for !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+func (c *sessionClock) phase(now time.Time) SessionPhase {
+	elapsed := now.Sub(c.start)
+	if c.cfg.Duration > 0 && elapsed >= c.cfg.Duration {
+		return SessionOver
+	}
+	if len(c.windows) > 0 {
+		if !now.Before(c.end) {
+			return SessionOver
+		}
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		sinceMidnight := now.Sub(midnight)
+		for _, w := range c.windows {
+			if w.contains(sinceMidnight) {
+				return SessionPlay
+			}
+		}
+		return SessionRest
+	}
+	if c.cfg.Play > 0 && c.cfg.Rest > 0 {
+		if elapsed%(c.cfg.Play+c.cfg.Rest) < c.cfg.Play {
+			return SessionPlay
+		}
+		return SessionRest
+	}
+	return SessionPlay
+}
@@ -0,0 +1,115 @@
+package kitty
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	windows, err := ParseSchedule(" 08:00-08:20, 22:30-01:15 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PlayWindow{
+		{Start: 8 * time.Hour, End: 8*time.Hour + 20*time.Minute},
+		{Start: 22*time.Hour + 30*time.Minute, End: time.Hour + 15*time.Minute},
+	}
+	if len(windows) != len(want) {
+		t.Fatalf("ParseSchedule = %v, want %v", windows, want)
+	}
+	for i := range want {
+		if windows[i] != want[i] {
+			t.Errorf("window %d = %v, want %v", i, windows[i], want[i])
+		}
+	}
+	if windows, err := ParseSchedule(""); err != nil || windows != nil {
+		t.Errorf("ParseSchedule(\"\") = %v, %v; want no windows", windows, err)
+	}
+	for _, bad := range []string{"08:00", "8-9", "08:00-25:00", "08:00-08:00", "08:00-08:20,"} {
+		if _, err := ParseSchedule(bad); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestPlayWindowContains(t *testing.T) {
+	day := PlayWindow{Start: 8 * time.Hour, End: 9 * time.Hour}
+	night := PlayWindow{Start: 23 * time.Hour, End: time.Hour}
+	for _, tc := range []struct {
+		w    PlayWindow
+		at   time.Duration
+		want bool
+	}{
+		{day, 8 * time.Hour, true},
+		{day, 9*time.Hour - time.Second, true},
+		{day, 9 * time.Hour, false},
+		{day, 7 * time.Hour, false},
+		{night, 23 * time.Hour, true},
+		{night, 0, true},
+		{night, 30 * time.Minute, true},
+		{night, time.Hour, false},
+		{night, 12 * time.Hour, false},
+	} {
+		if got := tc.w.contains(tc.at); got != tc.want {
+			t.Errorf("%v contains %v = %v, want %v", tc.w, tc.at, got, tc.want)
+		}
+	}
+}
+
+// at is a time of day on the given day of March 2026, in UTC.
+func at(day, hour, min int) time.Time {
+	return time.Date(2026, 3, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestSessionClockPhase(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		cfg   SessionConfig
+		start time.Time
+		now   time.Time
+		want  SessionPhase
+	}{
+		{"nothing set plays", SessionConfig{}, at(1, 9, 0), at(5, 9, 0), SessionPlay},
+		{"within duration", SessionConfig{Duration: 15 * time.Minute}, at(1, 9, 0), at(1, 9, 14), SessionPlay},
+		{"duration over", SessionConfig{Duration: 15 * time.Minute}, at(1, 9, 0), at(1, 9, 15), SessionOver},
+		{"play period", SessionConfig{Play: 5 * time.Minute, Rest: 10 * time.Minute}, at(1, 9, 0), at(1, 9, 4), SessionPlay},
+		{"rest period", SessionConfig{Play: 5 * time.Minute, Rest: 10 * time.Minute}, at(1, 9, 0), at(1, 9, 5), SessionRest},
+		{"next play period", SessionConfig{Play: 5 * time.Minute, Rest: 10 * time.Minute}, at(1, 9, 0), at(1, 9, 15), SessionPlay},
+		{"duration beats cycles", SessionConfig{Duration: time.Minute, Play: 5 * time.Minute, Rest: 10 * time.Minute}, at(1, 9, 0), at(1, 9, 2), SessionOver},
+		{"in a window", SessionConfig{Schedule: "08:00-08:20,18:00-18:30"}, at(1, 7, 0), at(1, 8, 10), SessionPlay},
+		{"between windows", SessionConfig{Schedule: "08:00-08:20,18:00-18:30"}, at(1, 7, 0), at(1, 12, 0), SessionRest},
+		{"final window", SessionConfig{Schedule: "08:00-08:20,18:00-18:30"}, at(1, 7, 0), at(1, 18, 29), SessionPlay},
+		{"after the final window", SessionConfig{Schedule: "08:00-08:20,18:00-18:30"}, at(1, 7, 0), at(1, 18, 30), SessionOver},
+		{"started after the final window", SessionConfig{Schedule: "08:00-08:20,18:00-18:30"}, at(1, 20, 0), at(2, 8, 5), SessionPlay},
+		{"rests until tomorrow", SessionConfig{Schedule: "08:00-08:20,18:00-18:30"}, at(1, 20, 0), at(1, 23, 0), SessionRest},
+		{"ends tomorrow", SessionConfig{Schedule: "08:00-08:20,18:00-18:30"}, at(1, 20, 0), at(2, 18, 30), SessionOver},
+		{"window past midnight", SessionConfig{Schedule: "23:00-01:00"}, at(1, 22, 0), at(2, 0, 30), SessionPlay},
+		{"window past midnight ends", SessionConfig{Schedule: "23:00-01:00"}, at(1, 22, 0), at(2, 1, 0), SessionOver},
+		{"started inside a window past midnight", SessionConfig{Schedule: "23:00-01:00"}, at(2, 0, 30), at(2, 0, 45), SessionPlay},
+		{"duration beats the schedule", SessionConfig{Duration: time.Hour, Schedule: "08:00-20:00"}, at(1, 8, 0), at(1, 9, 0), SessionOver},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newSessionClock(tc.cfg, tc.start)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.phase(tc.now); got != tc.want {
+				t.Errorf("phase = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewSessionClockErrors(t *testing.T) {
+	for _, cfg := range []SessionConfig{
+		{Play: 5 * time.Minute},
+		{Rest: 10 * time.Minute},
+		{Duration: -time.Minute},
+		{Play: -time.Minute, Rest: time.Minute},
+		{Schedule: "soon"},
+	} {
+		if _, err := newSessionClock(cfg, at(1, 9, 0)); err == nil {
+			t.Errorf("newSessionClock(%+v) succeeded", cfg)
+		}
+	}
+}
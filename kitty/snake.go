@@ -1,15 +1,16 @@
 package kitty
 
 import (
+	"fmt"
+	"log/slog"
 	"math"
-	"math/rand"
-	"time"
 
 	"github.com/gdamore/tcell/v3"
 	"github.com/gdamore/tcell/v3/color"
 )
 
 type Snake struct {
+	Lifecycle
 	MaxLen int
 	Color  tcell.Color
 
@@ -17,7 +18,6 @@ type Snake struct {
 	curLen      int
 	step        int
 	phase       float64
-	initialized bool
 	progress    float64
 	speed       float64
 	speedTarget float64
@@ -26,10 +26,19 @@ type Snake struct {
 	zoomOffTargetSet bool
 	zoomOffX float64
 	zoomOffY float64
-	respawnWait int
-	spawnDelay  int
-	initDelaySet bool
-	initialDelayMax int
+	intensity       float64
+	log             *slog.Logger
+	sprite          *Sprite
+	world           *World
+	targetLen       int
+	startLen        int
+	mealWait        int
+
+	seekWeight       float64
+	fleeWeight       float64
+	separationWeight float64
+	webWeight        float64
+	eatChance        float64
 
 	posX            float64
 	posY            float64
@@ -40,65 +49,60 @@ type Snake struct {
 	amplitudeTarget float64
 }
 
-var snakeRandSeeded bool
-var snakeAvoidPoints []Point
+// How far a snake looks for butterflies to chase, laser dots to flee, other
+// snakes to keep clear of and webs to avoid.
+const (
+	snakeSeekRadius       = 30.0
+	snakeFleeRadius       = 12.0
+	snakeSeparationRadius = 5.0
+	snakeWebRadius        = 6.0
+)
+
+// snakeMealWait is how many ticks a snake waits after snapping at a
+// butterfly before it may try again.
+const snakeMealWait = 30
 
-func SetSnakeAvoidPoints(points []Point) {
-	snakeAvoidPoints = points
+func (s *Snake) Name() string {
+	return "snake"
 }
 
-func (s *Snake) Draw(screen tcell.Screen) {
-	width, height := screen.Size()
+func (s *Snake) Layer() Layer {
+	return LayerCritters
+}
+
+func (s *Snake) Draw(c *Canvas) {
 	fg := s.Color
 	if fg == tcell.ColorDefault || fg == 0 {
 		fg = color.Green
 	}
-	for _, p := range s.body {
-		for dx := -1; dx <= 1; dx++ {
-			for dy := -1; dy <= 1; dy++ {
-				x := p.X + dx
-				y := p.Y + dy
-				if x < 0 || y < 0 || x >= width || y >= height {
-					continue
-				}
-				screen.SetContent(x, y, tcell.RuneBlock, nil, tcell.StyleDefault.Foreground(fg))
-			}
+	sprite := s.sprite
+	for i, p := range s.body {
+		frame := "body"
+		switch i {
+		case len(s.body) - 1:
+			frame = "head"
+		case 0:
+			frame = "tail"
 		}
+		sprite.Frame(frame).Draw(c, p.X, p.Y, fg, fg)
 	}
 }
 
-func (s *Snake) Update(screen tcell.Screen) {
+func (s *Snake) Update(w *World) {
+	s.world = w
 	if s.MaxLen <= 0 {
 		s.MaxLen = 10
 	}
-	if s.spawnDelay > 0 {
-		s.spawnDelay--
-		return
-	}
-	if s.respawnWait > 0 {
-		s.respawnWait--
+	if !s.Tick(s, w) {
 		return
 	}
-	if !s.initialized {
-		if !s.initDelaySet {
-			ensureSnakeSeeded()
-			maxDelay := s.initialDelayMax
-			if maxDelay <= 0 {
-				maxDelay = 40
-			}
-			s.spawnDelay = rand.Intn(maxDelay + 1)
-			s.initDelaySet = true
-		}
-		if s.spawnDelay > 0 {
-			s.spawnDelay--
-			return
-		}
-		s.initSnake(screen)
-	}
 
-	width, height := screen.Size()
+	width, height := w.Width, w.Height
+	if s.mealWait > 0 {
+		s.mealWait--
+	}
 	s.updateSpeed()
-	s.progress += s.speed
+	s.progress += s.speed * activity(s.intensity)
 	for s.progress >= 1.0 {
 		s.progress -= 1.0
 		s.updateSteering(width, height)
@@ -110,29 +114,110 @@ func (s *Snake) Update(screen tcell.Screen) {
 
 		head := s.nextHead()
 		s.body = append(s.body, head)
-		if s.curLen < s.MaxLen {
+		if s.curLen < s.targetLen {
 			s.curLen++
 		}
 		if len(s.body) > s.curLen {
 			s.body = s.body[len(s.body)-s.curLen:]
 		}
+		if s.world.Float64() < 0.25 {
+			tail := s.body[0]
+			s.world.Effects.Emit(fxSlitherDust, float64(tail.X), float64(tail.Y))
+		}
 	}
 
 	if s.shouldReset(width, height) {
-		s.respawnWait = 20 + rand.Intn(40)
-		s.initialized = false
-		s.body = s.body[:0]
+		s.Despawn(DespawnGone)
+	}
+}
+
+func (s *Snake) Spawn(w *World) {
+	s.world = w
+	s.sprite = w.Sprites.Pick("snake", w)
+	s.initSnake(w.Width, w.Height)
+}
+
+func (s *Snake) Despawn(reason DespawnReason) {
+	wait := 0
+	if reason != DespawnRemoved {
+		wait = calmWait(20+s.world.Intn(40), s.intensity)
+	}
+	s.body = s.body[:0]
+	s.Stop(wait)
+}
+
+func (s *Snake) Bounds() Rect {
+	return boundsOf(s.body, 1)
+}
+
+// Leave sends the snake zooming off the nearest way out instead of respawning.
+func (s *Snake) Leave() {
+	s.Lifecycle.Leave()
+	if s.Active() && s.zoomOffTicks == 0 {
+		s.zoomOffTicks = 1000
+		s.zoomOffTargetSet = false
+	}
+}
+
+func (s *Snake) SetIntensity(intensity float64) {
+	s.intensity = intensity
+}
+
+// BodyPoints returns the centers of the snake's body segments.
+func (s *Snake) BodyPoints() []Point {
+	return s.body
+}
+
+// Head returns where the snake's head is while it is hunting.
+func (s *Snake) Head() (Point, bool) {
+	if !s.Active() || s.Leaving() || len(s.body) == 0 {
+		return Point{}, false
+	}
+	return s.body[len(s.body)-1], true
+}
+
+// TryEat is called when the snake reaches a butterfly. It reports whether
+// the snake ate it, in which case the snake grows by one segment.
+func (s *Snake) TryEat() bool {
+	if s.mealWait > 0 {
+		return false
+	}
+	s.mealWait = snakeMealWait
+	if s.world.Float64() >= s.eatChance {
+		return false
+	}
+	s.targetLen = min(s.MaxLen, s.targetLen+1)
+	s.log.Debug("snake ate butterfly", "len", s.targetLen,
+		"x", math.Round(s.posX), "y", math.Round(s.posY))
+	return true
+}
+
+func (s *Snake) SetLogger(log *slog.Logger) {
+	s.log = log
+}
+
+func (s *Snake) DebugInfo() (int, int, string, bool) {
+	if !s.Active() || len(s.body) == 0 {
+		return 0, 0, "", false
+	}
+	state := "slither"
+	if s.zoomOffTicks > 0 {
+		state = "zoom-off"
+	} else if s.zoomTicks > 0 {
+		state = "zoom"
 	}
+	head := s.body[len(s.body)-1]
+	info := fmt.Sprintf("snake %s v=%.1f hdg=%.2f turn=%.2f len=%d/%d", state, s.speed, s.heading, s.turnTarget, s.curLen, s.targetLen)
+	return head.X, head.Y, info, true
 }
 
-func (s *Snake) initSnake(screen tcell.Screen) {
-	width, height := screen.Size()
+func (s *Snake) initSnake(width, height int) {
 	if width <= 0 || height <= 0 {
 		return
 	}
-	ensureSnakeSeeded()
+	s.Start()
 
-	side := rand.Intn(4)
+	side := s.world.Intn(4)
 	maxAmp := 6
 	if width < maxAmp*2 {
 		maxAmp = max(1, width/4)
@@ -146,39 +231,43 @@ func (s *Snake) initSnake(screen tcell.Screen) {
 	s.phase = 0
 	s.step = 0
 	s.curLen = 1
+	// a snake that starts short grows into its full length by eating
+	s.targetLen = s.MaxLen
+	if s.startLen > 0 {
+		s.targetLen = min(s.startLen, s.MaxLen)
+	}
+	s.mealWait = 0
 	s.progress = 0
 	s.speed = 1.0
 	s.speedTarget = 1.0
 	s.zoomTicks = 0
 	s.zoomOffTicks = 0
 	s.zoomOffTargetSet = false
-	s.respawnWait = 0
 	s.body = s.body[:0]
-	s.initialized = true
 	if s.Color == tcell.ColorDefault || s.Color == 0 {
-		s.Color = randomSnakeColor()
+		s.Color = randomSnakeColor(s.world)
 	}
 
 	if side == 0 { // left -> right
 		s.posX = -1
-		s.posY = randRange(0, float64(max(1, height-1)))
-		s.heading = randRange(-0.6, 0.6)
+		s.posY = s.world.Range(0, float64(max(1, height-1)))
+		s.heading = s.world.Range(-0.6, 0.6)
 	} else if side == 1 { // right -> left
 		s.posX = float64(width)
-		s.posY = randRange(0, float64(max(1, height-1)))
-		s.heading = randRange(math.Pi-0.6, math.Pi+0.6)
+		s.posY = s.world.Range(0, float64(max(1, height-1)))
+		s.heading = s.world.Range(math.Pi-0.6, math.Pi+0.6)
 	} else if side == 2 { // top -> bottom
-		s.posX = randRange(0, float64(max(1, width-1)))
+		s.posX = s.world.Range(0, float64(max(1, width-1)))
 		s.posY = -1
-		s.heading = randRange(math.Pi/2-0.6, math.Pi/2+0.6)
+		s.heading = s.world.Range(math.Pi/2-0.6, math.Pi/2+0.6)
 	} else { // bottom -> top
-		s.posX = randRange(0, float64(max(1, width-1)))
+		s.posX = s.world.Range(0, float64(max(1, width-1)))
 		s.posY = float64(height)
-		s.heading = randRange(-math.Pi/2-0.6, -math.Pi/2+0.6)
+		s.heading = s.world.Range(-math.Pi/2-0.6, -math.Pi/2+0.6)
 	}
 
 	s.turnTarget = s.heading
-	s.turnSpeed = randRange(0.03, 0.12)
+	s.turnSpeed = s.world.Range(0.03, 0.12)
 }
 
 func NewSnake(cfg SnakeConfig) *Snake {
@@ -188,32 +277,60 @@ func NewSnake(cfg SnakeConfig) *Snake {
 	if cfg.InitialDelayMax <= 0 {
 		cfg.InitialDelayMax = 40
 	}
+	def := DefaultSnakeConfig()
+	cfg.SeekWeight = steerWeight(cfg.SeekWeight, def.SeekWeight)
+	cfg.FleeWeight = steerWeight(cfg.FleeWeight, def.FleeWeight)
+	cfg.SeparationWeight = steerWeight(cfg.SeparationWeight, def.SeparationWeight)
+	cfg.WebWeight = steerWeight(cfg.WebWeight, def.WebWeight)
+	if cfg.EatChance <= 0 || cfg.EatChance > 1 {
+		cfg.EatChance = def.EatChance
+	}
 	return &Snake{
-		MaxLen:          cfg.MaxLen,
-		Color:           cfg.Color,
-		initialDelayMax: cfg.InitialDelayMax,
+		Lifecycle:        Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		MaxLen:           cfg.MaxLen,
+		Color:            cfg.Color,
+		intensity:        1.0,
+		log:              discardLogger,
+		seekWeight:       cfg.SeekWeight,
+		fleeWeight:       cfg.FleeWeight,
+		separationWeight: cfg.SeparationWeight,
+		webWeight:        cfg.WebWeight,
+		eatChance:        cfg.EatChance,
+		startLen:         cfg.StartLen,
+	}
+}
+
+// steerWeight is w, or def if w is zero; a negative w turns the behavior
+// off.
+func steerWeight(w, def float64) float64 {
+	if w == 0 {
+		return def
 	}
+	return math.Max(w, 0)
 }
 
 func (s *Snake) updateSpeed() {
 	if s.zoomOffTicks > 0 {
 		s.zoomOffTicks--
-		s.speedTarget = randRange(6.0, 9.0)
+		s.speedTarget = s.world.Range(6.0, 9.0)
 	} else if s.zoomTicks > 0 {
 		s.zoomTicks--
 	} else {
 		// small random drift while in normal mode
-		s.speedTarget += (rand.Float64() - 0.5) * 0.05
+		s.speedTarget += (s.world.Float64() - 0.5) * 0.05
 		s.speedTarget = clampFloat(s.speedTarget, 0.4, 1.6)
 		// occasional zoom burst
-		if rand.Float64() < 0.02 {
-			s.speedTarget = randRange(2.5, 5.0)
-			s.zoomTicks = 10 + rand.Intn(20)
+		if s.world.Float64() < 0.02*activity(s.intensity) {
+			s.speedTarget = s.world.Range(2.5, 5.0)
+			s.zoomTicks = 10 + s.world.Intn(20)
+			s.log.Debug("snake zoom", "ticks", s.zoomTicks, "speed", s.speedTarget)
 		}
 		// rare zoom-off to exit
-		if rand.Float64() < 0.006 {
-			s.zoomOffTicks = 20 + rand.Intn(30)
+		if s.world.Float64() < 0.006 {
+			s.zoomOffTicks = 20 + s.world.Intn(30)
 			s.zoomOffTargetSet = false
+			s.log.Debug("snake zoom-off", "ticks", s.zoomOffTicks,
+				"x", math.Round(s.posX), "y", math.Round(s.posY))
 		}
 	}
 	// smooth change toward target
@@ -223,7 +340,7 @@ func (s *Snake) updateSpeed() {
 func (s *Snake) updateSteering(width, height int) {
 	if s.zoomOffTicks > 0 {
 		if !s.zoomOffTargetSet {
-			s.zoomOffX, s.zoomOffY = randomEdgePoint(width, height)
+			s.zoomOffX, s.zoomOffY = randomEdgePoint(s.world, width, height)
 			s.zoomOffTargetSet = true
 		}
 		angle := math.Atan2(s.zoomOffY-s.posY, s.zoomOffX-s.posX)
@@ -241,21 +358,21 @@ func (s *Snake) updateSteering(width, height int) {
 		return
 	}
 	// drift target heading a bit for chaos
-	s.turnTarget += (rand.Float64()-0.5)*0.08 + math.Sin(s.phase)*0.01
+	s.turnTarget += (s.world.Float64()-0.5)*0.08 + math.Sin(s.phase)*0.01
 	// occasional bigger turn
-	if rand.Float64() < 0.03 {
-		s.turnTarget = s.heading + randRange(-1.2, 1.2)
+	if s.world.Float64() < 0.03 {
+		s.turnTarget = s.heading + s.world.Range(-1.2, 1.2)
 	}
 	// sometimes aim toward a random edge to allow any exit
-	if rand.Float64() < 0.015 {
-		x, y := randomEdgePoint(width, height)
+	if s.world.Float64() < 0.015 {
+		x, y := randomEdgePoint(s.world, width, height)
 		angle := math.Atan2(y-s.posY, x-s.posX)
 		s.turnTarget = angle
 	}
 
 	// smooth amplitude changes
-	if rand.Float64() < 0.02 {
-		s.amplitudeTarget = randRange(2.0, 10.0)
+	if s.world.Float64() < 0.02 {
+		s.amplitudeTarget = s.world.Range(2.0, 10.0)
 	}
 	// reduce wiggle when moving fast
 	ampScale := clampFloat(2.0/(s.speed+0.5), 0.35, 1.0)
@@ -263,7 +380,7 @@ func (s *Snake) updateSteering(width, height int) {
 	s.amplitude += (adjTarget - s.amplitude) * 0.05
 
 	// rotate heading toward target
-	s.applyWebAvoidance()
+	s.steer()
 	delta := normalizeAngle(s.turnTarget - s.heading)
 	if delta > s.turnSpeed {
 		delta = s.turnSpeed
@@ -273,29 +390,77 @@ func (s *Snake) updateSteering(width, height int) {
 	s.heading = normalizeAngle(s.heading + delta)
 }
 
-func (s *Snake) applyWebAvoidance() {
-	if len(snakeAvoidPoints) == 0 {
+// steer bends the turn target by the weighted pull of everything the snake
+// can see: the nearest butterfly draws it in, while laser dots, other snakes
+// and webs push it away.
+func (s *Snake) steer() {
+	if s.world == nil {
 		return
 	}
-	const avoidRadius = 6.0
 	var ax, ay float64
-	for _, p := range snakeAvoidPoints {
-		dx := s.posX - float64(p.X)
-		dy := s.posY - float64(p.Y)
-		dist := math.Hypot(dx, dy)
-		if dist <= 0 || dist > avoidRadius {
-			continue
+	if s.seekWeight > 0 {
+		if p, dist, ok := nearestPoint(s.posX, s.posY, s.world.Butterflies); ok && dist > 0 && dist < snakeSeekRadius {
+			ax += (float64(p.X) - s.posX) / dist * s.seekWeight
+			ay += (float64(p.Y) - s.posY) / dist * s.seekWeight
 		}
-		strength := (avoidRadius - dist) / avoidRadius
-		ax += (dx / dist) * strength
-		ay += (dy / dist) * strength
+	}
+	if s.fleeWeight > 0 {
+		fx, fy := repel(s.posX, s.posY, s.world.Lasers, snakeFleeRadius)
+		ax += fx * s.fleeWeight
+		ay += fy * s.fleeWeight
+	}
+	if s.separationWeight > 0 {
+		for _, other := range s.world.Snakes {
+			if other == s {
+				continue
+			}
+			sx, sy := repel(s.posX, s.posY, other.BodyPoints(), snakeSeparationRadius)
+			ax += sx * s.separationWeight
+			ay += sy * s.separationWeight
+		}
+	}
+	if s.webWeight > 0 {
+		wx, wy := repel(s.posX, s.posY, s.world.WebPoints, snakeWebRadius)
+		ax += wx * s.webWeight
+		ay += wy * s.webWeight
 	}
 	if ax == 0 && ay == 0 {
 		return
 	}
-	avoidAngle := math.Atan2(ay, ax)
-	s.turnTarget = blendAngle(s.turnTarget, avoidAngle, 0.65)
-	s.turnSpeed = maxFloat(s.turnSpeed, 0.2)
+	pull := math.Min(1, math.Hypot(ax, ay))
+	s.turnTarget = blendAngle(s.turnTarget, math.Atan2(ay, ax), 0.65*pull)
+	s.turnSpeed = maxFloat(s.turnSpeed, 0.2*pull)
+}
+
+// nearestPoint finds the point closest to (x, y).
+func nearestPoint(x, y float64, points []Point) (Point, float64, bool) {
+	best := Point{}
+	bestDist := math.Inf(1)
+	for _, p := range points {
+		if d := math.Hypot(float64(p.X)-x, float64(p.Y)-y); d < bestDist {
+			best = p
+			bestDist = d
+		}
+	}
+	return best, bestDist, len(points) > 0
+}
+
+// repel sums unit vectors pointing away from every point within radius of
+// (x, y), each weighted by how close the point is.
+func repel(x, y float64, points []Point, radius float64) (float64, float64) {
+	var rx, ry float64
+	for _, p := range points {
+		dx := x - float64(p.X)
+		dy := y - float64(p.Y)
+		dist := math.Hypot(dx, dy)
+		if dist <= 0 || dist > radius {
+			continue
+		}
+		strength := (radius - dist) / radius
+		rx += (dx / dist) * strength
+		ry += (dy / dist) * strength
+	}
+	return rx, ry
 }
 
 func (s *Snake) nextHead() Point {
@@ -336,11 +501,7 @@ func clampFloat(v, minV, maxV float64) float64 {
 	return v
 }
 
-func randRange(minV, maxV float64) float64 {
-	return minV + rand.Float64()*(maxV-minV)
-}
-
-func randomSnakeColor() tcell.Color {
+func randomSnakeColor(w *World) tcell.Color {
 	colors := []tcell.Color{
 		color.Red,
 		color.Orange,
@@ -355,7 +516,7 @@ func randomSnakeColor() tcell.Color {
 		color.Maroon,
 		color.Lime,
 	}
-	return colors[rand.Intn(len(colors))]
+	return colors[w.Intn(len(colors))]
 }
 
 func normalizeAngle(a float64) float64 {
@@ -380,27 +541,20 @@ func maxFloat(a, b float64) float64 {
 	return b
 }
 
-func randomEdgePoint(width, height int) (float64, float64) {
+func randomEdgePoint(w *World, width, height int) (float64, float64) {
 	if width <= 0 || height <= 0 {
 		return 0, 0
 	}
-	edge := rand.Intn(4)
+	edge := w.Intn(4)
 	if edge == 0 { // left
-		return -1, randRange(0, float64(height-1))
+		return -1, w.Range(0, float64(height-1))
 	}
 	if edge == 1 { // right
-		return float64(width), randRange(0, float64(height-1))
+		return float64(width), w.Range(0, float64(height-1))
 	}
 	if edge == 2 { // top
-		return randRange(0, float64(width-1)), -1
+		return w.Range(0, float64(width-1)), -1
 	}
 	// bottom
-	return randRange(0, float64(width-1)), float64(height)
-}
-
-func ensureSnakeSeeded() {
-	if !snakeRandSeeded {
-		rand.Seed(time.Now().UnixNano())
-		snakeRandSeeded = true
-	}
+	return w.Range(0, float64(width-1)), float64(height)
 }
@@ -1,22 +1,18 @@
 package kitty
 
 import (
+	"fmt"
+	"log/slog"
 	"math"
-	"math/rand"
-	"time"
 
 	"github.com/gdamore/tcell/v3"
 	"github.com/gdamore/tcell/v3/color"
 )
 
 type Spider struct {
+	Lifecycle
 	Color tcell.Color
 
-	active          bool
-	respawnWait     int
-	initDelaySet    bool
-	initialDelayMax int
-
 	x            float64
 	y            float64
 	targetX      float64
@@ -24,15 +20,17 @@ type Spider struct {
 	speed        float64
 	pauseTicks   int
 	legPhase     float64
-	explosionTicks int
-	explosionX     int
-	explosionY     int
 	
 	// Web building
-	webSegments    []Point
-	webSpokes      []Point
+	web            []webThread
+	strands        []webStrand
+	webCells       map[Point]struct{}
+	webPlan        []webStep
+	webShape       webShape
+	webConfig      SpiderConfig
 	dropSilk       []Point
-	webState       string // "dropping", "building", "done", "hunting", "eating", "climbing"
+	state          spiderState
+	stateTicks     int
 	webBuildStep   int
 	dropStartY     float64
 	dropTargetY    float64
@@ -42,45 +40,49 @@ type Spider struct {
 	// Hunting
 	preyX          float64
 	preyY          float64
-	eatingTicks    int
 	webIncomplete  bool
+
+	intensity      float64
+	log            *slog.Logger
+	sprite         *Sprite
+	world          *World
+
+	screenWidth    int
+	screenHeight   int
+}
+
+func (s *Spider) Name() string {
+	return "spider"
 }
 
-var spiderRandSeeded bool
+func (s *Spider) Layer() Layer {
+	return LayerCritters
+}
 
-func (s *Spider) Update(screen tcell.Screen) {
-	width, height := screen.Size()
+func (s *Spider) Update(w *World) {
+	s.world = w
+	width, height := w.Width, w.Height
 	if width <= 0 || height <= 0 {
 		return
 	}
-	if s.explosionTicks > 0 {
-		s.explosionTicks--
+	if !s.Tick(s, w) {
 		return
 	}
-	ensureSpiderSeeded()
 
-	if !s.active && !s.initDelaySet {
-		maxDelay := s.initialDelayMax
-		if maxDelay <= 0 {
-			maxDelay = 60
-		}
-		s.respawnWait = rand.Intn(maxDelay + 1)
-		s.initDelaySet = true
-	}
-	if s.respawnWait > 0 {
-		s.respawnWait--
+	s.legPhase += 0.5
+	s.handleResize(width, height)
+	if !s.Active() {
 		return
 	}
-	if !s.active {
-		s.initSpider(width, height)
+
+	s.stateTicks++
+	if limit := spiderTimeouts[s.state]; limit > 0 && s.stateTicks > limit {
+		s.stateTimedOut()
 		return
 	}
 
-	s.legPhase += 0.5
-
-	// State machine for spider web building
-	switch s.webState {
-	case "dropping":
+	switch s.state {
+	case spiderDropping:
 		// Drop down from top on silk thread
 		s.y += 0.8
 		// Add silk thread
@@ -89,224 +91,230 @@ func (s *Spider) Update(screen tcell.Screen) {
 			s.dropSilk = append(s.dropSilk, webPoint)
 		}
 		if s.y >= s.dropTargetY {
-			s.webState = "building"
-			s.centerX = s.x
-			s.centerY = s.y
-			s.webBuildStep = 0
+			s.setState(spiderBuilding)
 		}
-		return
 
-	case "building":
-		// Build classic spider web
-		s.buildClassicWeb(width, height)
-		return
+	case spiderBuilding:
+		s.buildWeb(width, height)
 
-	case "done":
+	case spiderDone:
 		// Web is complete, spider rests at center
-		if s.pauseTicks > 0 {
+		if _, _, damaged := s.damagedStrand(); damaged {
+			s.setState(spiderRepairing)
+		} else if s.pauseTicks > 0 {
 			s.pauseTicks--
 		} else {
 			// After resting, climb back up
-			s.webState = "climbing"
-			s.x = s.centerX
-			s.y = s.centerY
+			s.setState(spiderClimbing)
 		}
-		return
-	
-	case "hunting":
+
+	case spiderHunting:
 		// Move towards prey
 		dx := s.preyX - s.x
 		dy := s.preyY - s.y
 		dist := math.Hypot(dx, dy)
-		if dist < 1.0 {
-			// Reached prey, start eating
-			s.webState = "eating"
-			s.eatingTicks = 20
-		} else {
+		if dist >= 1.0 {
 			// Move quickly towards prey
-			speed := 1.5
+			speed := math.Min(1.5*activity(s.intensity), dist)
 			s.x += (dx / dist) * speed
 			s.y += (dy / dist) * speed
 		}
-		return
-	
-	case "eating":
+
+	case spiderEating:
 		// Eating animation
-		if s.eatingTicks > 0 {
-			s.eatingTicks--
+		if s.stateTicks >= spiderEatTicks {
+			s.setState(s.afterPrey())
+		}
+
+	case spiderReturning:
+		// Return to center
+		dx := s.centerX - s.x
+		dy := s.centerY - s.y
+		dist := math.Hypot(dx, dy)
+		if dist < 1.0 {
+			s.setState(spiderDone)
 		} else {
-			// Return to center or resume building
-			if s.webIncomplete {
-				// Resume building web
-				s.webState = "building"
-				s.webIncomplete = false
-			} else {
-				// Return to center
-				dx := s.centerX - s.x
-				dy := s.centerY - s.y
-				dist := math.Hypot(dx, dy)
-				if dist < 1.0 {
-					s.x = s.centerX
-					s.y = s.centerY
-					s.webState = "done"
-					s.pauseTicks = 100 + rand.Intn(100)
-				} else {
-					speed := 1.0
-					s.x += (dx / dist) * speed
-					s.y += (dy / dist) * speed
-				}
-			}
+			speed := 1.0
+			s.x += (dx / dist) * speed
+			s.y += (dy / dist) * speed
 		}
-		return
-	
-	case "climbing":
+
+	case spiderRepairing:
+		s.repairWeb()
+
+	case spiderClimbing:
 		// Climb back up to top
 		s.y -= 0.8
 		if s.y <= 0 {
 			// Reached top, despawn and clear web
-			s.active = false
-			s.respawnWait = 200 + rand.Intn(300)
-			s.webSegments = []Point{}
-			s.webSpokes = []Point{}
-			s.dropSilk = []Point{}
+			s.Despawn(DespawnGone)
 		}
-		return
 	}
 }
 
-func (s *Spider) buildClassicWeb(width, height int) {
-	// Build a classic spider web with radial spokes and concentric circles
-	const numSpokes = 8
-	const numRings = 4
-	const maxRadius = 8.0
-	
-	step := s.webBuildStep
-	
-	// Phase 1: Build spokes progressively (first 100 steps)
-	if step < numSpokes*25 {
-		spokeIndex := step / 25
-		spokeProgress := float64(step % 25) / 25.0
-		
-		if spokeIndex < numSpokes {
-			angle := (float64(spokeIndex) / float64(numSpokes)) * 2 * math.Pi
-			radius := maxRadius * spokeProgress
-			
-			// Move spider outward along the spoke
-			s.x = s.centerX + math.Cos(angle)*radius
-			s.y = s.centerY + math.Sin(angle)*radius
-			
-			// Add spoke segment
-			px := int(math.Round(s.x))
-			py := int(math.Round(s.y))
-			if px >= 0 && py >= 0 && px < width && py < height {
-				s.webSpokes = append(s.webSpokes, Point{X: px, Y: py})
-			}
+func (s *Spider) Spawn(w *World) {
+	s.world = w
+	s.Start()
+	s.sprite = w.Sprites.Pick("spider", w)
+	s.initSpider(w.Width, w.Height)
+}
+
+// Despawn takes the spider and its web off screen. A spider shot by the
+// laser bursts where it was hit.
+func (s *Spider) Despawn(reason DespawnReason) {
+	wait := 0
+	switch reason {
+	case DespawnGone:
+		wait = calmWait(200+s.world.Intn(300), s.intensity)
+	case DespawnHit:
+		x, y := int(math.Round(s.x)), int(math.Round(s.y))
+		s.log.Debug("spider destroyed", "x", x, "y", y, "state", s.state.String())
+		s.world.Effects.Emit(fxBurst, float64(x), float64(y))
+		wait = 40 + s.world.Intn(80)
+	}
+	s.setState(spiderIdle)
+	s.Stop(wait)
+}
+
+func (s *Spider) Bounds() Rect {
+	r := boundsOf(s.dropSilk, 0)
+	for _, t := range s.web {
+		r = r.Union(rectAround(t.X, t.Y, 0, 0))
+	}
+	if s.Active() {
+		r = r.Union(rectAround(int(math.Round(s.x)), int(math.Round(s.y)), 1, 2))
+	}
+	return r
+}
+
+// handleResize drops the parts of the web that no longer fit on screen. A
+// spider whose web center is gone abandons the web and climbs out.
+func (s *Spider) handleResize(width, height int) {
+	if width == s.screenWidth && height == s.screenHeight {
+		return
+	}
+	first := s.screenWidth == 0
+	s.screenWidth = width
+	s.screenHeight = height
+	if first {
+		return
+	}
+	kept := s.web[:0]
+	for _, t := range s.web {
+		if t.X < width && t.Y < height {
+			kept = append(kept, t)
+		} else {
+			delete(s.webCells, t.Point)
 		}
-		s.webBuildStep++
+	}
+	s.web = kept
+	s.dropSilk = pointsWithin(s.dropSilk, width, height)
+	if s.state == spiderIdle || s.state == spiderClimbing || s.state == spiderDropping {
 		return
 	}
-	
-	// Phase 2: Build concentric circles
-	circleStep := step - numSpokes*25
-	if circleStep < numRings*50 {
-		ringIndex := circleStep / 50
-		if ringIndex < numRings {
-			radius := maxRadius * float64(ringIndex+1) / float64(numRings)
-			angle := (float64(circleStep%50) / 50.0) * 2 * math.Pi
-			
-			// Move spider along the ring being built
-			s.x = s.centerX + math.Cos(angle)*radius
-			s.y = s.centerY + math.Sin(angle)*radius
-			
-			// Add web segment
-			px := int(math.Round(s.x))
-			py := int(math.Round(s.y))
-			if px >= 0 && py >= 0 && px < width && py < height {
-				s.webSegments = append(s.webSegments, Point{X: px, Y: py})
-			}
+	if s.centerX >= float64(width) || s.centerY >= float64(height) {
+		s.log.Debug("spider web lost to resize", "width", width, "height", height)
+		s.x = math.Min(s.x, float64(width-1))
+		s.y = math.Min(s.y, float64(height-1))
+		s.setState(spiderClimbing)
+	}
+}
+
+func pointsWithin(points []Point, width, height int) []Point {
+	kept := points[:0]
+	for _, p := range points {
+		if p.X >= 0 && p.Y >= 0 && p.X < width && p.Y < height {
+			kept = append(kept, p)
 		}
-		s.webBuildStep++
-	} else {
-		// Web complete, move spider to center
-		s.x = s.centerX
-		s.y = s.centerY
-		s.webState = "done"
-		s.pauseTicks = 300 + rand.Intn(200)
 	}
+	return kept
 }
 
-func (s *Spider) Draw(screen tcell.Screen) {
-	if s.explosionTicks > 0 {
-		s.drawExplosion(screen)
+// buildWeb walks the spider one step along its web plan, laying silk.
+func (s *Spider) buildWeb(width, height int) {
+	if s.webBuildStep >= len(s.webPlan) {
+		// Web complete, move spider to center
+		s.setState(spiderDone)
+		return
+	}
+	step := s.webPlan[s.webBuildStep]
+	s.webBuildStep++
+	s.x = step.x
+	s.y = step.y
+	t := step.thread
+	if t.X < 0 || t.Y < 0 || t.X >= width || t.Y >= height {
 		return
 	}
-	width, height := screen.Size()
+	if _, ok := s.webCells[t.Point]; ok {
+		return
+	}
+	s.webCells[t.Point] = struct{}{}
+	s.web = append(s.web, t)
+}
+
+// clearWeb removes the whole web, including the drop thread.
+func (s *Spider) clearWeb() {
+	s.web = []webThread{}
+	s.strands = nil
+	s.webCells = map[Point]struct{}{}
+	s.dropSilk = []Point{}
+}
+
+func (s *Spider) Draw(c *Canvas) {
+	width, height := c.Size()
 	
+	// silk hangs on the webs layer, under snakes and butterflies
+	prev := c.SetLayer(LayerWebs)
+
 	// Draw drop silk (not collidable) - same color as spokes
 	spokeColor := tcell.ColorDarkGray
 	for _, p := range s.dropSilk {
-		if p.X >= 0 && p.Y >= 0 && p.X < width && p.Y < height {
-			screen.SetContent(p.X, p.Y, '|', nil, tcell.StyleDefault.Foreground(spokeColor))
-		}
+		c.Set(p.X, p.Y, '|', spokeColor)
 	}
 	
-	// Draw web spokes first (radial lines)
-	for _, p := range s.webSpokes {
-		if p.X >= 0 && p.Y >= 0 && p.X < width && p.Y < height {
-			screen.SetContent(p.X, p.Y, '|', nil, tcell.StyleDefault.Foreground(spokeColor))
-		}
-	}
-	
-	// Draw web segments (concentric circles)
+	// Draw web: frame threads darker than the sticky capture threads,
+	// frayed strands faded and broken ones not at all
 	webColor := tcell.ColorGray
-	for _, p := range s.webSegments {
-		if p.X >= 0 && p.Y >= 0 && p.X < width && p.Y < height {
-			screen.SetContent(p.X, p.Y, '-', nil, tcell.StyleDefault.Foreground(webColor))
+	for _, t := range s.web {
+		integrity := s.strands[t.strand].integrity
+		if integrity <= 0 {
+			continue
 		}
+		fg := spokeColor
+		if t.capture {
+			fg = webColor
+		}
+		if integrity < 0.5 {
+			fg = color.DarkSlateGray
+		}
+		c.Set(t.X, t.Y, t.r, fg)
 	}
-	
-	if !s.active {
-		return
-	}
-	
-	cx := int(math.Round(s.x))
-	cy := int(math.Round(s.y))
-	if cx < 0 || cy < 0 || cx >= width || cy >= height {
+	c.SetLayer(prev)
+
+	cx, cy, ok := s.HitPoint(width, height)
+	if !ok {
 		return
 	}
 
 	fg := s.Color
 	if fg == tcell.ColorDefault || fg == 0 {
-		fg = randomSpiderColor()
+		fg = randomSpiderColor(s.world)
 		s.Color = fg
 	}
 
-	// body
-	s.drawCell(screen, cx, cy, 'o', fg, width, height)
-
 	// legs alternate with phase
-	legOffset := int(math.Round(math.Sin(s.legPhase)))
-	s.drawCell(screen, cx-1, cy+legOffset, '-', fg, width, height)
-	s.drawCell(screen, cx+1, cy-legOffset, '-', fg, width, height)
-	s.drawCell(screen, cx-1, cy-1+legOffset, '/', fg, width, height)
-	s.drawCell(screen, cx+1, cy-1-legOffset, '\\', fg, width, height)
-	s.drawCell(screen, cx-1, cy+1+legOffset, '\\', fg, width, height)
-	s.drawCell(screen, cx+1, cy+1-legOffset, '/', fg, width, height)
-}
-
-func (s *Spider) Hit(x, y int) {
-	s.active = false
-	s.explosionTicks = 6
-	s.explosionX = x
-	s.explosionY = y
-	s.respawnWait = 40 + rand.Intn(80)
-	s.webSegments = []Point{}
-	s.webSpokes = []Point{}
-	s.dropSilk = []Point{}
+	frame := "rest"
+	switch int(math.Round(math.Sin(s.legPhase))) {
+	case -1:
+		frame = "left"
+	case 1:
+		frame = "right"
+	}
+	s.sprite.Frame(frame).Draw(c, cx, cy, fg, fg)
 }
 
 func (s *Spider) HitPoint(width, height int) (int, int, bool) {
-	if !s.active {
+	if !s.Active() {
 		return 0, 0, false
 	}
 	cx := int(math.Round(s.x))
@@ -318,82 +326,105 @@ func (s *Spider) HitPoint(width, height int) (int, int, bool) {
 }
 
 func (s *Spider) GetWebPoints() []Point {
-	points := make([]Point, 0, len(s.webSegments)+len(s.webSpokes))
-	points = append(points, s.webSegments...)
-	points = append(points, s.webSpokes...)
+	points := make([]Point, 0, len(s.web))
+	for _, t := range s.web {
+		if !s.strands[t.strand].broken() {
+			points = append(points, t.Point)
+		}
+	}
 	return points
 }
 
 func (s *Spider) HuntPrey(x, y float64) {
-	if s.webState == "done" || s.webState == "building" {
-		s.preyX = x
-		s.preyY = y
-		// Remember if web was still being built
-		s.webIncomplete = (s.webState == "building")
-		s.webState = "hunting"
+	switch s.state {
+	case spiderDone, spiderBuilding, spiderReturning, spiderRepairing:
+		if s.setState(spiderHunting) {
+			s.preyX = x
+			s.preyY = y
+		}
+	}
+}
+
+// EatPrey starts eating once the spider has reached its prey.
+func (s *Spider) EatPrey() {
+	if s.state == spiderHunting {
+		s.setState(spiderEating)
 	}
 }
 
+// PreyLost gives up the hunt when the prey escaped the web or was destroyed
+// before the spider got to it.
+func (s *Spider) PreyLost() {
+	if s.state == spiderHunting {
+		s.log.Debug("spider lost prey", "preyX", s.preyX, "preyY", s.preyY)
+		s.setState(s.afterPrey())
+	}
+}
+
+// PreyPoint is where the prey the spider is hunting was caught.
+func (s *Spider) PreyPoint() (int, int) {
+	return int(math.Round(s.preyX)), int(math.Round(s.preyY))
+}
+
 func (s *Spider) IsHunting() bool {
-	return s.webState == "hunting" || s.webState == "eating"
+	return s.state == spiderHunting || s.state == spiderEating
 }
 
 func (s *Spider) GetCenterPoint() (float64, float64) {
 	return s.centerX, s.centerY
 }
 
-func (s *Spider) drawCell(screen tcell.Screen, x, y int, r rune, fg tcell.Color, width, height int) {
-	if x < 0 || y < 0 || x >= width || y >= height {
-		return
+// Leave abandons whatever the spider is doing and sends it climbing back up
+// its thread, taking the web with it.
+func (s *Spider) Leave() {
+	s.Lifecycle.Leave()
+	if s.Active() {
+		s.setState(spiderClimbing)
 	}
-	screen.SetContent(x, y, r, nil, tcell.StyleDefault.Foreground(fg))
 }
 
-func (s *Spider) drawExplosion(screen tcell.Screen) {
-	width, height := screen.Size()
-	fg := color.Yellow
-	if s.explosionTicks <= 2 {
-		fg = color.Red
-	}
-	center := Point{X: s.explosionX, Y: s.explosionY}
-	for _, p := range []Point{
-		center,
-		{X: center.X - 1, Y: center.Y},
-		{X: center.X + 1, Y: center.Y},
-		{X: center.X, Y: center.Y - 1},
-		{X: center.X, Y: center.Y + 1},
-		{X: center.X - 1, Y: center.Y - 1},
-		{X: center.X + 1, Y: center.Y - 1},
-		{X: center.X - 1, Y: center.Y + 1},
-		{X: center.X + 1, Y: center.Y + 1},
-	} {
-		if p.X < 0 || p.Y < 0 || p.X >= width || p.Y >= height {
-			continue
-		}
-		screen.SetContent(p.X, p.Y, tcell.RuneBullet, nil, tcell.StyleDefault.Foreground(fg))
+func (s *Spider) SetIntensity(intensity float64) {
+	s.intensity = intensity
+}
+
+func (s *Spider) SetLogger(log *slog.Logger) {
+	s.log = log
+}
+
+func (s *Spider) DebugInfo() (int, int, string, bool) {
+	if !s.Active() {
+		return 0, 0, "", false
+	}
+	info := fmt.Sprintf("spider %s %s web=%d", s.state, s.webShape, len(s.web))
+	if s.IsHunting() {
+		info += fmt.Sprintf(" prey=(%.0f,%.0f)", s.preyX, s.preyY)
 	}
+	return int(math.Round(s.x)), int(math.Round(s.y)), info, true
 }
 
 func (s *Spider) initSpider(width, height int) {
-	s.active = true
+	s.webShape = pickWebShape(s.world, s.webConfig.WebShape)
 	// Start at top of screen
-	s.x = spiderRandRange(float64(width/4), float64(3*width/4))
+	s.x = s.world.Range(float64(width/4), float64(3*width/4))
 	s.y = 0
 	// Drop down to middle area
-	s.dropTargetY = spiderRandRange(float64(height/4), float64(3*height/4))
-	s.webState = "dropping"
+	s.dropTargetY = s.world.Range(float64(height/4), float64(3*height/4))
+	if s.webShape == webCorner {
+		// sheet webs hang just below one of the top corners
+		s.x = s.world.Range(3, math.Max(3, float64(width)/6))
+		if s.world.Intn(2) == 0 {
+			s.x = float64(width-1) - s.x
+		}
+		s.dropTargetY = s.world.Range(2, math.Max(2, float64(height)/5))
+	}
+	s.setState(spiderDropping)
 	s.pauseTicks = 0
-	s.legPhase = spiderRandRange(0, math.Pi*2)
+	s.legPhase = s.world.Range(0, math.Pi*2)
 	if s.Color == tcell.ColorDefault || s.Color == 0 {
-		s.Color = randomSpiderColor()
+		s.Color = randomSpiderColor(s.world)
 	} else if s.Color == color.White {
-		s.Color = randomSpiderColor()
+		s.Color = randomSpiderColor(s.world)
 	}
-	s.webSegments = []Point{}
-	s.webSpokes = []Point{}
-	s.dropSilk = []Point{}
-	s.webBuildStep = 0
-	s.webIncomplete = false
 }
 
 func NewSpider(cfg SpiderConfig) *Spider {
@@ -401,12 +432,15 @@ func NewSpider(cfg SpiderConfig) *Spider {
 		cfg.InitialDelayMax = 60
 	}
 	return &Spider{
-		Color:           cfg.Color,
-		initialDelayMax: cfg.InitialDelayMax,
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		Color:     cfg.Color,
+		intensity: 1.0,
+		log:       discardLogger,
+		webConfig: cfg,
 	}
 }
 
-func randomSpiderColor() tcell.Color {
+func randomSpiderColor(w *World) tcell.Color {
 	colors := []tcell.Color{
 		color.Gray,
 		color.DarkGray,
@@ -414,16 +448,5 @@ func randomSpiderColor() tcell.Color {
 		color.Brown,
 		color.DarkRed,
 	}
-	return colors[rand.Intn(len(colors))]
-}
-
-func spiderRandRange(minV, maxV float64) float64 {
-	return minV + rand.Float64()*(maxV-minV)
-}
-
-func ensureSpiderSeeded() {
-	if !spiderRandSeeded {
-		rand.Seed(time.Now().UnixNano())
-		spiderRandSeeded = true
-	}
+	return colors[w.Intn(len(colors))]
 }
@@ -0,0 +1,187 @@
+package kitty
+
+import (
+	"math"
+)
+
+type spiderState int
+
+// A spider's life: it drops in on a thread, builds a web, rests in the
+// middle of it, hunts and eats whatever gets stuck, mends torn strands and
+// eventually climbs back out. Idle means it is not on screen.
+const (
+	spiderIdle spiderState = iota
+	spiderDropping
+	spiderBuilding
+	spiderDone
+	spiderHunting
+	spiderEating
+	spiderReturning
+	spiderRepairing
+	spiderClimbing
+)
+
+func (st spiderState) String() string {
+	switch st {
+	case spiderIdle:
+		return "idle"
+	case spiderDropping:
+		return "dropping"
+	case spiderBuilding:
+		return "building"
+	case spiderDone:
+		return "done"
+	case spiderHunting:
+		return "hunting"
+	case spiderEating:
+		return "eating"
+	case spiderReturning:
+		return "returning"
+	case spiderRepairing:
+		return "repairing"
+	case spiderClimbing:
+		return "climbing"
+	}
+	return "unknown"
+}
+
+// spiderTransitions lists the states each state may move to. Every state
+// can fall back to idle when the spider is destroyed.
+var spiderTransitions = map[spiderState][]spiderState{
+	spiderIdle:      {spiderDropping},
+	spiderDropping:  {spiderBuilding, spiderClimbing, spiderIdle},
+	spiderBuilding:  {spiderDone, spiderHunting, spiderClimbing, spiderIdle},
+	spiderDone:      {spiderHunting, spiderRepairing, spiderClimbing, spiderIdle},
+	spiderHunting:   {spiderEating, spiderReturning, spiderBuilding, spiderClimbing, spiderIdle},
+	spiderEating:    {spiderReturning, spiderBuilding, spiderClimbing, spiderIdle},
+	spiderReturning: {spiderDone, spiderHunting, spiderClimbing, spiderIdle},
+	spiderRepairing: {spiderReturning, spiderHunting, spiderClimbing, spiderIdle},
+	spiderClimbing:  {spiderIdle},
+}
+
+// spiderTimeouts bounds how many ticks a spider may spend in a state before
+// stateTimedOut moves it on, so a spider can never get stuck.
+var spiderTimeouts = map[spiderState]int{
+	spiderDropping:  400,
+	spiderBuilding:  2000,
+	spiderHunting:   150,
+	spiderReturning: 150,
+	spiderRepairing: 600,
+	spiderClimbing:  400,
+}
+
+// spiderEatTicks is how long a spider spends eating its catch.
+const spiderEatTicks = 20
+
+func canSpiderTransition(from, to spiderState) bool {
+	for _, next := range spiderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// setState moves the spider to a new state, running the leave hook of the
+// old state and the enter hook of the new one. Illegal transitions are
+// logged and refused.
+func (s *Spider) setState(to spiderState) bool {
+	from := s.state
+	if from == to {
+		return true
+	}
+	if !canSpiderTransition(from, to) {
+		s.log.Warn("illegal spider transition", "from", from.String(), "to", to.String())
+		return false
+	}
+	s.log.Debug("spider state", "from", from.String(), "to", to.String(),
+		"x", math.Round(s.x), "y", math.Round(s.y))
+	s.leaveState(to)
+	s.state = to
+	s.stateTicks = 0
+	s.enterState(from)
+	return true
+}
+
+func (s *Spider) enterState(from spiderState) {
+	switch s.state {
+	case spiderIdle:
+		s.clearWeb()
+	case spiderDropping:
+		s.clearWeb()
+		s.webPlan = nil
+		s.webBuildStep = 0
+		s.webIncomplete = false
+	case spiderBuilding:
+		if from == spiderDropping {
+			s.centerX = s.x
+			s.centerY = s.y
+			s.webBuildStep = 0
+			s.webPlan = planWeb(s.world, s.webShape, s.centerX, s.centerY, newWebSize(s.world, s.webConfig), s.screenWidth, s.screenHeight)
+			s.strands = nil
+			if n := len(s.webPlan); n > 0 {
+				s.strands = make([]webStrand, s.webPlan[n-1].thread.strand+1)
+				for i := range s.strands {
+					s.strands[i].integrity = 1
+				}
+			}
+		}
+		s.webIncomplete = false
+	case spiderDone:
+		s.x = s.centerX
+		s.y = s.centerY
+		if from == spiderBuilding {
+			s.pauseTicks = 300 + s.world.Intn(200)
+		} else {
+			s.pauseTicks = 100 + s.world.Intn(100)
+		}
+	}
+}
+
+func (s *Spider) leaveState(to spiderState) {
+	switch s.state {
+	case spiderBuilding:
+		// remember to finish the web after dealing with prey
+		s.webIncomplete = to == spiderHunting
+	case spiderHunting:
+		// the prey point stays while the spider eats what it caught
+		if to != spiderEating {
+			s.clearPrey()
+		}
+	case spiderEating:
+		s.clearPrey()
+	}
+}
+
+func (s *Spider) clearPrey() {
+	s.preyX = 0
+	s.preyY = 0
+}
+
+// afterPrey is where a spider goes once it is done with prey, whether it ate
+// it or the prey got away.
+func (s *Spider) afterPrey() spiderState {
+	if s.webIncomplete {
+		return spiderBuilding
+	}
+	return spiderReturning
+}
+
+// stateTimedOut moves a spider on when it has been in a state too long.
+func (s *Spider) stateTimedOut() {
+	s.log.Debug("spider state timed out", "state", s.state.String(), "ticks", s.stateTicks)
+	switch s.state {
+	case spiderHunting:
+		s.setState(s.afterPrey())
+	case spiderReturning:
+		s.setState(spiderDone)
+	case spiderRepairing:
+		s.setState(spiderReturning)
+	case spiderDropping:
+		s.setState(spiderBuilding)
+	case spiderBuilding:
+		s.setState(spiderDone)
+	case spiderClimbing:
+		s.Despawn(DespawnGone)
+	}
+}
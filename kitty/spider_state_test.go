@@ -0,0 +1,288 @@
+package kitty
+
+import (
+	"testing"
+)
+
+// newTestSpider spawns a spider on an 80x24 world. It starts out dropping.
+func newTestSpider(t *testing.T) (*Spider, *World) {
+	t.Helper()
+	w := NewWorld(1)
+	w.Width, w.Height = 80, 24
+	cfg := DefaultSpiderConfig()
+	cfg.WebShape = "orb"
+	s := NewSpider(cfg)
+	s.Spawn(w)
+	s.handleResize(w.Width, w.Height)
+	if s.state != spiderDropping {
+		t.Fatalf("new spider is %s, want dropping", s.state)
+	}
+	return s, w
+}
+
+// buildingSpider is a spider that has dropped and started its web.
+func buildingSpider(t *testing.T) (*Spider, *World) {
+	t.Helper()
+	s, w := newTestSpider(t)
+	s.y = s.dropTargetY
+	if !s.setState(spiderBuilding) {
+		t.Fatal("dropping spider did not start building")
+	}
+	return s, w
+}
+
+// doneSpider is a spider resting in the middle of a finished web.
+func doneSpider(t *testing.T) (*Spider, *World) {
+	t.Helper()
+	s, w := buildingSpider(t)
+	for i := 0; s.state == spiderBuilding; i++ {
+		if i > 10000 {
+			t.Fatal("web never finished")
+		}
+		s.buildWeb(w.Width, w.Height)
+	}
+	if s.state != spiderDone {
+		t.Fatalf("spider is %s after building, want done", s.state)
+	}
+	return s, w
+}
+
+var allSpiderStates = []spiderState{
+	spiderIdle, spiderDropping, spiderBuilding, spiderDone, spiderHunting,
+	spiderEating, spiderReturning, spiderRepairing, spiderClimbing,
+}
+
+func TestSpiderTransitions(t *testing.T) {
+	for _, from := range allSpiderStates {
+		for _, to := range allSpiderStates {
+			if from == to {
+				continue
+			}
+			s, _ := buildingSpider(t)
+			s.state = from
+			ok := s.setState(to)
+			if want := canSpiderTransition(from, to); ok != want {
+				t.Errorf("%s -> %s: setState = %v, want %v", from, to, ok, want)
+			}
+			if ok && s.state != to {
+				t.Errorf("%s -> %s: spider is %s", from, to, s.state)
+			}
+			if !ok && s.state != from {
+				t.Errorf("%s -> %s refused but spider is %s", from, to, s.state)
+			}
+			if ok && s.stateTicks != 0 {
+				t.Errorf("%s -> %s: stateTicks = %d, want 0", from, to, s.stateTicks)
+			}
+		}
+	}
+}
+
+func TestSpiderTransitionsReachIdle(t *testing.T) {
+	for _, from := range allSpiderStates {
+		if from != spiderIdle && !canSpiderTransition(from, spiderIdle) {
+			t.Errorf("%s cannot fall back to idle", from)
+		}
+	}
+}
+
+func TestSpiderTimeouts(t *testing.T) {
+	next := map[spiderState]spiderState{
+		spiderDropping:  spiderBuilding,
+		spiderBuilding:  spiderDone,
+		spiderHunting:   spiderReturning,
+		spiderReturning: spiderDone,
+		spiderRepairing: spiderReturning,
+		spiderClimbing:  spiderIdle,
+	}
+	for state, limit := range spiderTimeouts {
+		want, ok := next[state]
+		if !ok {
+			t.Errorf("no expectation for the %s timeout", state)
+			continue
+		}
+		s, w := doneSpider(t)
+		s.state = state
+		s.stateTicks = limit
+		s.Update(w)
+		if s.state != want {
+			t.Errorf("%s timed out to %s, want %s", state, s.state, want)
+		}
+	}
+}
+
+func TestSpiderTimeoutBeforeLimit(t *testing.T) {
+	s, w := doneSpider(t)
+	s.HuntPrey(s.x+10, s.y)
+	s.stateTicks = spiderTimeouts[spiderHunting] - 1
+	s.Update(w)
+	if s.state != spiderHunting {
+		t.Errorf("hunting spider is %s one tick before its timeout", s.state)
+	}
+}
+
+func TestSpiderHuntAndEat(t *testing.T) {
+	s, w := doneSpider(t)
+	s.HuntPrey(s.x+4, s.y+2)
+	if s.state != spiderHunting {
+		t.Fatalf("spider is %s after HuntPrey, want hunting", s.state)
+	}
+	px, py := s.PreyPoint()
+	s.EatPrey()
+	if s.state != spiderEating {
+		t.Fatalf("spider is %s after EatPrey, want eating", s.state)
+	}
+	if x, y := s.PreyPoint(); x != px || y != py {
+		t.Errorf("eating spider's prey is at (%d,%d), want (%d,%d)", x, y, px, py)
+	}
+	for i := 0; i < spiderEatTicks; i++ {
+		s.Update(w)
+	}
+	if s.state != spiderReturning {
+		t.Fatalf("spider is %s after eating, want returning", s.state)
+	}
+	if x, y := s.PreyPoint(); x != 0 || y != 0 {
+		t.Errorf("prey point is (%d,%d) after eating, want cleared", x, y)
+	}
+	for i := 0; s.state == spiderReturning; i++ {
+		if i > spiderTimeouts[spiderReturning] {
+			t.Fatal("spider never got back to its web")
+		}
+		s.Update(w)
+	}
+	if s.state != spiderDone {
+		t.Errorf("spider is %s after returning, want done", s.state)
+	}
+}
+
+func TestSpiderPreyEscapes(t *testing.T) {
+	s, _ := doneSpider(t)
+	s.HuntPrey(s.x+4, s.y+2)
+	s.PreyLost()
+	if s.state != spiderReturning {
+		t.Errorf("spider is %s after losing its prey, want returning", s.state)
+	}
+	if x, y := s.PreyPoint(); x != 0 || y != 0 {
+		t.Errorf("prey point is (%d,%d) after the prey escaped, want cleared", x, y)
+	}
+	if s.IsHunting() {
+		t.Error("spider still hunting escaped prey")
+	}
+}
+
+func TestSpiderPreyEscapesWhileBuilding(t *testing.T) {
+	s, w := buildingSpider(t)
+	for i := 0; i < 5; i++ {
+		s.buildWeb(w.Width, w.Height)
+	}
+	step := s.webBuildStep
+	s.HuntPrey(s.x+2, s.y)
+	if s.state != spiderHunting || !s.webIncomplete {
+		t.Fatalf("spider is %s (incomplete %v), want hunting an unfinished web", s.state, s.webIncomplete)
+	}
+	s.PreyLost()
+	if s.state != spiderBuilding {
+		t.Fatalf("spider is %s after losing its prey, want back to building", s.state)
+	}
+	if s.webBuildStep != step {
+		t.Errorf("web restarted at step %d, want %d", s.webBuildStep, step)
+	}
+}
+
+func TestSpiderIgnoresPreyWhenBusy(t *testing.T) {
+	s, _ := newTestSpider(t)
+	s.HuntPrey(10, 10)
+	if s.state != spiderDropping {
+		t.Errorf("dropping spider went %s for prey", s.state)
+	}
+	s.EatPrey()
+	s.PreyLost()
+	if s.state != spiderDropping {
+		t.Errorf("EatPrey/PreyLost moved a dropping spider to %s", s.state)
+	}
+
+	s, _ = doneSpider(t)
+	s.HuntPrey(s.x+4, s.y)
+	s.EatPrey()
+	s.HuntPrey(1, 1)
+	if s.state != spiderEating {
+		t.Errorf("eating spider went %s for more prey", s.state)
+	}
+}
+
+func TestSpiderPreyShotMidHunt(t *testing.T) {
+	k := newTestKitty(t, DefaultKittyConfig())
+	w := k.world
+
+	spider := NewSpider(SpiderConfig{WebShape: "orb", WebRadius: 6, WebSpokes: 8, WebRings: 3})
+	spider.Spawn(w)
+	spider.handleResize(w.Width, w.Height)
+	spider.y = spider.dropTargetY
+	spider.setState(spiderBuilding)
+	for spider.state == spiderBuilding {
+		spider.buildWeb(w.Width, w.Height)
+	}
+
+	butterfly := NewButterfly(DefaultButterflyConfig())
+	butterfly.Spawn(w)
+	web := spider.GetWebPoints()
+	target := web[len(web)-1]
+	butterfly.x = float64(target.X)
+	butterfly.baseY = float64(target.Y)
+	butterfly.waveAmp = 0
+	butterfly.wavePhase = 0
+	px, py, ok := butterfly.HitPoint(w.Width, w.Height)
+	if !ok {
+		t.Fatal("butterfly is off screen")
+	}
+
+	laser := NewLaserPointer(DefaultLaserConfig())
+	laser.Spawn(w)
+	laser.x, laser.y = -10, -10
+
+	k.objects = []KittyPlayThing{spider, butterfly, laser}
+	k.handleWebCollisions()
+	if !butterfly.IsStuckInWeb() || spider.state != spiderHunting {
+		t.Fatalf("butterfly stuck %v, spider %s; want stuck and hunting", butterfly.IsStuckInWeb(), spider.state)
+	}
+
+	// the laser shoots the butterfly before the spider gets there
+	laser.x, laser.y = float64(px), float64(py)
+	k.handleLaserHits()
+	if butterfly.Active() {
+		t.Fatal("laser missed the stuck butterfly")
+	}
+	k.handleWebCollisions()
+	if spider.state != spiderReturning {
+		t.Errorf("spider is %s after its prey was shot, want returning", spider.state)
+	}
+	if x, y := spider.PreyPoint(); x != 0 || y != 0 {
+		t.Errorf("prey point is (%d,%d) after the prey was shot, want cleared", x, y)
+	}
+}
+
+func TestSpiderResizeWhileBuilding(t *testing.T) {
+	s, w := buildingSpider(t)
+	for i := 0; i < 10; i++ {
+		s.buildWeb(w.Width, w.Height)
+	}
+
+	// shrinking around the web keeps the spider building what still fits
+	s.handleResize(int(s.centerX)+3, int(s.centerY)+3)
+	if s.state != spiderBuilding {
+		t.Fatalf("spider is %s after a resize that kept its web, want building", s.state)
+	}
+	for _, p := range s.web {
+		if p.X >= int(s.centerX)+3 || p.Y >= int(s.centerY)+3 {
+			t.Errorf("thread at (%d,%d) survived the resize", p.X, p.Y)
+		}
+	}
+
+	// losing the web center sends it climbing out
+	s.handleResize(int(s.centerX)-1, int(s.centerY)-1)
+	if s.state != spiderClimbing {
+		t.Errorf("spider is %s after losing its web to a resize, want climbing", s.state)
+	}
+	if s.x >= s.centerX || s.y >= s.centerY {
+		t.Errorf("spider left at (%.0f,%.0f), off the shrunk screen", s.x, s.y)
+	}
+}
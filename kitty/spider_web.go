@@ -0,0 +1,358 @@
+package kitty
+
+import (
+	"fmt"
+	"math"
+)
+
+type webShape int
+
+const (
+	webOrb webShape = iota
+	webSpiral
+	webCobweb
+	webCorner
+)
+
+var webShapeNames = map[string]webShape{
+	"orb":    webOrb,
+	"spiral": webSpiral,
+	"cobweb": webCobweb,
+	"corner": webCorner,
+}
+
+func (w webShape) String() string {
+	for name, shape := range webShapeNames {
+		if shape == w {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// ValidateWebShape reports whether name is a web shape a spider can build.
+// An empty name or "random" picks a shape per web.
+func ValidateWebShape(name string) error {
+	if name == "" || name == "random" {
+		return nil
+	}
+	if _, ok := webShapeNames[name]; !ok {
+		return fmt.Errorf("unknown web shape %q (want orb, spiral, cobweb, corner or random)", name)
+	}
+	return nil
+}
+
+func pickWebShape(w *World, name string) webShape {
+	if shape, ok := webShapeNames[name]; ok {
+		return shape
+	}
+	return webShape(w.Intn(len(webShapeNames)))
+}
+
+// webThread is one laid cell of web. Frame threads are the radial spokes and
+// anchor lines, capture threads the sticky rings and spirals between them.
+type webThread struct {
+	Point
+	r       rune
+	capture bool
+	strand  int
+}
+
+// webStrand is a short run of threads that tears and is repaired as a unit.
+// Integrity runs from 1 (intact) down to 0 (broken).
+type webStrand struct {
+	integrity float64
+}
+
+func (st webStrand) broken() bool {
+	return st.integrity <= 0
+}
+
+// webStrandLen is how many threads make up one strand.
+const webStrandLen = 6
+
+// webStep is one tick of web building: where the spider walks to and the
+// thread it leaves behind there.
+type webStep struct {
+	x, y   float64
+	thread webThread
+}
+
+// Terminal cells are roughly twice as tall as they are wide, so webs are
+// stretched horizontally to look round.
+const cellAspect = 2.0
+
+// webStepLen is how far the spider walks per tick while building, in rows.
+const webStepLen = 0.6
+
+// webSteps is how many steps a spider takes along a thread spanning cols
+// columns and rows rows that is length rows long. It walks webStepLen rows
+// per step but never more than one cell along either axis, so mostly
+// horizontal threads have no gaps.
+func webSteps(cols, rows, length float64) int {
+	n := math.Max(length/webStepLen, math.Max(math.Abs(cols), math.Abs(rows)))
+	return max(1, int(math.Ceil(n)))
+}
+
+type webPlanner struct {
+	world  *World
+	steps  []webStep
+	strand int
+	run    int
+}
+
+// begin starts a new strand for the next line or arc.
+func (p *webPlanner) begin() {
+	if len(p.steps) > 0 {
+		p.strand++
+	}
+	p.run = 0
+}
+
+// line walks from (x0, y0) to (x1, y1) laying silk.
+func (p *webPlanner) line(x0, y0, x1, y1 float64, capture bool) {
+	p.begin()
+	dx := x1 - x0
+	dy := y1 - y0
+	n := webSteps(dx, dy, math.Hypot(dx/cellAspect, dy))
+	r := webRune(dx, dy)
+	for i := 0; i <= n; i++ {
+		u := float64(i) / float64(n)
+		p.lay(x0+dx*u, y0+dy*u, r, capture)
+	}
+}
+
+// arc walks around (cx, cy) from angle a0 to a1 while the radius changes
+// from r0 to r1, which covers both rings and spirals.
+func (p *webPlanner) arc(cx, cy, r0, r1, a0, a1 float64, capture bool) {
+	p.begin()
+	sweep := a1 - a0
+	// the longest the arc can run along either axis
+	span := math.Abs(sweep)*math.Max(r0, r1) + math.Abs(r1-r0)
+	n := webSteps(span*cellAspect, span, span)
+	for i := 0; i <= n; i++ {
+		u := float64(i) / float64(n)
+		a := a0 + sweep*u
+		r := r0 + (r1-r0)*u
+		// tangent of the curve picks the rune
+		dr := (r1 - r0) / sweep
+		tx := (dr*math.Cos(a) - r*math.Sin(a)) * cellAspect
+		ty := dr*math.Sin(a) + r*math.Cos(a)
+		if sweep < 0 {
+			tx, ty = -tx, -ty
+		}
+		p.lay(cx+math.Cos(a)*r*cellAspect, cy+math.Sin(a)*r, webRune(tx, ty), capture)
+	}
+}
+
+func (p *webPlanner) lay(x, y float64, r rune, capture bool) {
+	if p.run >= webStrandLen {
+		p.strand++
+		p.run = 0
+	}
+	p.run++
+	p.steps = append(p.steps, webStep{
+		x: x,
+		y: y,
+		thread: webThread{
+			Point:   Point{X: int(math.Round(x)), Y: int(math.Round(y))},
+			r:       r,
+			capture: capture,
+			strand:  p.strand,
+		},
+	})
+}
+
+// webRune picks the character that best follows a thread heading (dx, dy).
+func webRune(dx, dy float64) rune {
+	deg := math.Atan2(dy*cellAspect, dx) * 180 / math.Pi
+	if deg < 0 {
+		deg += 180
+	}
+	switch {
+	case deg < 22.5 || deg >= 157.5:
+		return '-'
+	case deg < 67.5:
+		return '\\'
+	case deg < 112.5:
+		return '|'
+	}
+	return '/'
+}
+
+// webSize holds the dimensions of one web, resolved from SpiderConfig.
+type webSize struct {
+	radius float64
+	spokes int
+	rings  int
+}
+
+func newWebSize(w *World, cfg SpiderConfig) webSize {
+	size := webSize{radius: float64(cfg.WebRadius), spokes: cfg.WebSpokes, rings: cfg.WebRings}
+	if size.radius <= 0 {
+		size.radius = 6 + w.Float64()*4
+	}
+	if size.spokes <= 0 {
+		size.spokes = 6 + w.Intn(7)
+	}
+	if size.rings <= 0 {
+		size.rings = 3 + w.Intn(4)
+	}
+	return size
+}
+
+// planWeb lays out the order in which a spider builds a web of the given
+// shape around (cx, cy). Threads are numbered into strands from 0.
+func planWeb(w *World, shape webShape, cx, cy float64, size webSize, width, height int) []webStep {
+	p := &webPlanner{world: w}
+	switch shape {
+	case webOrb, webSpiral:
+		planOrb(p, shape, cx, cy, size)
+	case webCobweb:
+		planCobweb(p, cx, cy, size)
+	case webCorner:
+		planCorner(p, cx, cy, size, width, height)
+	}
+	return p.steps
+}
+
+func planOrb(p *webPlanner, shape webShape, cx, cy float64, size webSize) {
+	offset := p.world.Float64() * math.Pi
+	for i := 0; i < size.spokes; i++ {
+		angle := offset + float64(i)/float64(size.spokes)*2*math.Pi
+		// spokes vary a little in length like real anchor lines
+		r := size.radius * (0.85 + p.world.Float64()*0.15)
+		p.line(cx, cy, cx+math.Cos(angle)*r*cellAspect, cy+math.Sin(angle)*r, false)
+		p.line(cx+math.Cos(angle)*r*cellAspect, cy+math.Sin(angle)*r, cx, cy, false)
+	}
+	if shape == webSpiral {
+		// capture thread spirals in from the rim towards the hub
+		turns := float64(size.rings)
+		p.arc(cx, cy, size.radius*0.9, size.radius*0.15, offset, offset+turns*2*math.Pi, true)
+		return
+	}
+	for ring := 1; ring <= size.rings; ring++ {
+		r := size.radius * float64(ring) / float64(size.rings)
+		p.arc(cx, cy, r, r, offset, offset+2*math.Pi, true)
+	}
+}
+
+// planCobweb strings a tangle of threads between random anchor points.
+func planCobweb(p *webPlanner, cx, cy float64, size webSize) {
+	anchors := make([][2]float64, 0, size.spokes)
+	for i := 0; i < size.spokes; i++ {
+		angle := p.world.Float64() * 2 * math.Pi
+		r := size.radius * (0.3 + p.world.Float64()*0.7)
+		anchors = append(anchors, [2]float64{cx + math.Cos(angle)*r*cellAspect, cy + math.Sin(angle)*r})
+	}
+	x, y := cx, cy
+	for i := 0; i < size.spokes+size.rings*2; i++ {
+		a := anchors[p.world.Intn(len(anchors))]
+		p.line(x, y, a[0], a[1], i%2 == 1)
+		x, y = a[0], a[1]
+	}
+	p.line(x, y, cx, cy, true)
+}
+
+// planCorner builds a sheet web across the nearest top corner: frame lines
+// from the corner and hammock lines strung between the two screen edges.
+func planCorner(p *webPlanner, cx, cy float64, size webSize, width, height int) {
+	cornerX := 0.0
+	dir := 1.0
+	if cx > float64(width)/2 {
+		cornerX = float64(width - 1)
+		dir = -1
+	}
+	spanX := math.Min(size.radius*cellAspect*1.5, float64(width-1))
+	spanY := math.Min(size.radius*1.5, float64(height-1))
+	p.line(cx, cy, cornerX, 0, false)
+	for i := 1; i <= size.spokes; i++ {
+		u := float64(i) / float64(size.spokes+1)
+		angle := u * math.Pi / 2
+		p.line(cornerX, 0, cornerX+dir*math.Cos(angle)*spanX, math.Sin(angle)*spanY, false)
+		p.line(cornerX+dir*math.Cos(angle)*spanX, math.Sin(angle)*spanY, cornerX, 0, false)
+	}
+	for i := 1; i <= size.rings; i++ {
+		u := float64(i) / float64(size.rings)
+		p.line(cornerX+dir*spanX*u, 0, cornerX, spanY*u, true)
+	}
+	p.line(cornerX, 0, cx, cy, false)
+}
+
+// DamageWeb weakens every strand with a thread within radius cells of (x, y)
+// by amount, once per strand however many of its threads are in reach. It
+// returns how many strands broke.
+func (s *Spider) DamageWeb(x, y, radius int, amount float64) int {
+	// the first thread in reach of each strand, in the order they were laid
+	var hit []webThread
+	seen := map[int]bool{}
+	for _, t := range s.web {
+		if absInt(t.X-x) > radius || absInt(t.Y-y) > radius || seen[t.strand] {
+			continue
+		}
+		seen[t.strand] = true
+		hit = append(hit, t)
+	}
+	broke := 0
+	for _, t := range hit {
+		st := &s.strands[t.strand]
+		if st.broken() {
+			continue
+		}
+		st.integrity -= amount
+		if st.broken() {
+			st.integrity = 0
+			broke++
+			s.world.Effects.Emit(fxWebSnap, float64(t.X), float64(t.Y))
+		}
+	}
+	if broke > 0 {
+		s.log.Debug("web strands broken", "x", x, "y", y, "count", broke)
+	}
+	return broke
+}
+
+// WebHolds reports whether an intact thread is within one cell of (x, y),
+// i.e. whether something stuck there is still held by the web.
+func (s *Spider) WebHolds(x, y int) bool {
+	for _, t := range s.web {
+		if absInt(t.X-x) <= 1 && absInt(t.Y-y) <= 1 && !s.strands[t.strand].broken() {
+			return true
+		}
+	}
+	return false
+}
+
+// damagedStrand finds a strand in need of repair and the first thread of it
+// to walk to.
+func (s *Spider) damagedStrand() (int, Point, bool) {
+	for _, t := range s.web {
+		if s.strands[t.strand].integrity < 1 {
+			return t.strand, t.Point, true
+		}
+	}
+	return 0, Point{}, false
+}
+
+// repairWeb walks to the damaged strand and re-spins it.
+func (s *Spider) repairWeb() {
+	strand, p, ok := s.damagedStrand()
+	if !ok {
+		s.setState(spiderReturning)
+		return
+	}
+	dx := float64(p.X) - s.x
+	dy := float64(p.Y) - s.y
+	dist := math.Hypot(dx, dy)
+	if dist >= 1.0 {
+		speed := math.Min(1.0*activity(s.intensity), dist)
+		s.x += (dx / dist) * speed
+		s.y += (dy / dist) * speed
+		return
+	}
+	st := &s.strands[strand]
+	st.integrity = math.Min(1, st.integrity+0.15)
+	if st.integrity >= 1 {
+		s.log.Debug("web strand repaired", "x", p.X, "y", p.Y)
+	}
+}
@@ -0,0 +1,64 @@
+package kitty
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// checkNoGaps fails if two consecutive steps land more than a cell apart.
+func checkNoGaps(t *testing.T, name string, steps []webStep) {
+	t.Helper()
+	for i := 1; i < len(steps); i++ {
+		a, b := steps[i-1].thread.Point, steps[i].thread.Point
+		if absInt(a.X-b.X) > 1 || absInt(a.Y-b.Y) > 1 {
+			t.Errorf("%s: gap between (%d,%d) and (%d,%d)", name, a.X, a.Y, b.X, b.Y)
+		}
+	}
+}
+
+func TestWebPlannerLinesHaveNoGaps(t *testing.T) {
+	for _, end := range [][2]float64{{40, 6}, {-40, 1}, {3, 20}, {30, 15}} {
+		p := &webPlanner{world: NewWorld(1)}
+		p.line(40, 5, end[0], end[1], false)
+		checkNoGaps(t, "line", p.steps)
+	}
+}
+
+func TestWebPlannerArcsHaveNoGaps(t *testing.T) {
+	p := &webPlanner{world: NewWorld(1)}
+	p.arc(40, 12, 8, 8, 0, 2*math.Pi, true)
+	checkNoGaps(t, "ring", p.steps)
+
+	p = &webPlanner{world: NewWorld(1)}
+	p.arc(40, 12, 9, 1, 0, 6*math.Pi, true)
+	checkNoGaps(t, "spiral", p.steps)
+}
+
+func TestNewRejectsUnknownWebShape(t *testing.T) {
+	cfg := DefaultKittyConfig()
+	cfg.SpiderConfig.WebShape = "hexagon"
+	_, err := newKitty(cfg, newMockScreen)
+	if err == nil || !strings.Contains(err.Error(), "hexagon") {
+		t.Errorf("New with web shape hexagon: err = %v", err)
+	}
+}
+
+func TestDamageWebHitsEachStrandOnce(t *testing.T) {
+	s, _ := doneSpider(t)
+	// a strand with many threads in reach loses amount once
+	s.web = nil
+	s.strands = []webStrand{{integrity: 1}, {integrity: 1}}
+	for x := 10; x < 15; x++ {
+		s.web = append(s.web, webThread{Point: Point{X: x, Y: 5}, strand: 0})
+	}
+	s.web = append(s.web, webThread{Point: Point{X: 12, Y: 6}, strand: 1})
+	if broke := s.DamageWeb(12, 5, 2, 0.3); broke != 0 {
+		t.Errorf("DamageWeb broke %d strands, want 0", broke)
+	}
+	for i, st := range s.strands {
+		if math.Abs(st.integrity-0.7) > 1e-9 {
+			t.Errorf("strand %d integrity = %.2f, want 0.70", i, st.integrity)
+		}
+	}
+}
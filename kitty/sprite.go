@@ -0,0 +1,609 @@
+package kitty
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Sprite is a named set of frames, each a grid of runes drawn around the
+// critter's position. A critter picks a frame by name, or steps through the
+// sprite's loop for an animation.
+type Sprite struct {
+	Name   string
+	Frames []*SpriteFrame
+	// Loop is the animation order, as indexes into Frames; by default
+	// every frame in turn.
+	Loop []int
+	// Ticks is how many steps each frame of the loop shows for.
+	Ticks int
+
+	// file and line are where the sprite was defined.
+	file string
+	line int
+}
+
+// SpriteFrame is one picture of a sprite. The origin cell is drawn at the
+// critter's position; cells holding a space are see-through.
+type SpriteFrame struct {
+	Name    string
+	Width   int
+	Height  int
+	OriginX int
+	OriginY int
+
+	cells   []spriteCell
+	flipped *SpriteFrame
+}
+
+type spriteCell struct {
+	r     rune
+	paint spritePaint
+}
+
+// spritePaint is a cell's color: the critter's own color (fg), its
+// highlight (hi) or a fixed color.
+type spritePaint struct {
+	role  spriteRole
+	color tcell.Color
+}
+
+type spriteRole int
+
+const (
+	paintFg spriteRole = iota
+	paintHi
+	paintFixed
+)
+
+// Frame returns the frame called name, or the first frame if there is
+// none.
+func (s *Sprite) Frame(name string) *SpriteFrame {
+	if f := s.frame(name); f != nil {
+		return f
+	}
+	return s.Frames[0]
+}
+
+// At returns the frame showing at the given step of the loop.
+func (s *Sprite) At(step int) *SpriteFrame {
+	loop := s.Loop
+	if len(loop) == 0 {
+		return s.Frames[0]
+	}
+	i := step / max(1, s.Ticks) % len(loop)
+	if i < 0 {
+		i += len(loop)
+	}
+	return s.Frames[loop[i]]
+}
+
+// Flip returns the frame mirrored left to right, for a critter facing the
+// other way. Runes with a mirror image, such as '/' and '(', are swapped
+// for it.
+func (f *SpriteFrame) Flip() *SpriteFrame {
+	return f.flipped
+}
+
+// Draw draws the frame with its origin at (x, y). Cells painted with the fg
+// or hi role take the given colors.
+func (f *SpriteFrame) Draw(c *Canvas, x, y int, fg, hi tcell.Color) {
+	for i, cell := range f.cells {
+		if cell.r == ' ' {
+			continue
+		}
+		col := fg
+		switch cell.paint.role {
+		case paintHi:
+			col = hi
+		case paintFixed:
+			col = cell.paint.color
+		}
+		c.Set(x+i%f.Width-f.OriginX, y+i/f.Width-f.OriginY, cell.r, col)
+	}
+}
+
+// mirrorRunes pairs runes with their left-right mirror images.
+var mirrorRunes = map[rune]rune{
+	'/': '\\', '\\': '/',
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'd': 'b', 'b': 'd',
+	'p': 'q', 'q': 'p',
+	'▌': '▐', '▐': '▌',
+	'┌': '┐', '┐': '┌',
+	'└': '┘', '┘': '└',
+	'├': '┤', '┤': '├',
+}
+
+func (f *SpriteFrame) mirror() *SpriteFrame {
+	m := &SpriteFrame{
+		Name:    f.Name,
+		Width:   f.Width,
+		Height:  f.Height,
+		OriginX: f.Width - 1 - f.OriginX,
+		OriginY: f.OriginY,
+		cells:   make([]spriteCell, len(f.cells)),
+		flipped: f,
+	}
+	for i, cell := range f.cells {
+		x, y := i%f.Width, i/f.Width
+		if r, ok := mirrorRunes[cell.r]; ok {
+			cell.r = r
+		}
+		m.cells[y*f.Width+f.Width-1-x] = cell
+	}
+	return m
+}
+
+// SpriteError is a problem in a sprite file, at a given line.
+type SpriteError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *SpriteError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// ParseSprites reads sprites in the text format of the files under
+// kitty/sprites:
+//
+//	# comment
+//	sprite <name>             starts a sprite
+//	origin <x> <y>            the cell drawn at the critter's position
+//	ticks <n>                 steps each frame of the loop shows for
+//	color <key> <color>       a paint key: fg, hi, a color name or #rrggbb
+//	loop <frame> ...          the animation order
+//	frame <name>              rune grid lines follow, up to "end"
+//	paint <frame>             paint keys for the frame's cells, up to "end"
+//
+// Spaces in a frame are see-through, and a space or '.' in a paint grid
+// means fg. Errors name the file and line.
+func ParseSprites(file string, r io.Reader) ([]*Sprite, error) {
+	p := &spriteParser{file: file}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		p.line++
+		if err := p.parseLine(strings.TrimRight(sc.Text(), "\r")); err != nil {
+			return nil, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if p.block != nil {
+		return nil, p.errorAt(p.block.line, "%s %q has no \"end\"", p.block.kind, p.block.name)
+	}
+	if err := p.finish(); err != nil {
+		return nil, err
+	}
+	if len(p.sprites) == 0 {
+		return nil, p.errorAt(p.line, "no sprites")
+	}
+	return p.sprites, nil
+}
+
+type spriteParser struct {
+	file    string
+	line    int
+	sprites []*Sprite
+
+	cur     *Sprite
+	curLine int
+	originX int
+	originY int
+	// originLine is where the origin was set, or the sprite line.
+	originLine int
+	colors     map[rune]spritePaint
+	paints     map[string][]string
+	loop       []string
+	loopLine   int
+	block      *spriteBlock
+}
+
+// spriteBlock is a frame or paint grid being read.
+type spriteBlock struct {
+	kind string
+	name string
+	line int
+	rows []string
+}
+
+func (p *spriteParser) errorAt(line int, format string, args ...any) error {
+	return &SpriteError{File: p.file, Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *spriteParser) parseLine(text string) error {
+	if p.block != nil {
+		if strings.TrimSpace(text) == "end" {
+			return p.endBlock()
+		}
+		p.block.rows = append(p.block.rows, text)
+		return nil
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil
+	}
+	directive, args := fields[0], fields[1:]
+	if directive != "sprite" && p.cur == nil {
+		return p.errorAt(p.line, "%q before the first \"sprite\"", directive)
+	}
+	switch directive {
+	case "sprite":
+		if len(args) != 1 {
+			return p.errorAt(p.line, "want \"sprite <name>\"")
+		}
+		if err := p.finish(); err != nil {
+			return err
+		}
+		for _, s := range p.sprites {
+			if s.Name == args[0] {
+				return p.errorAt(p.line, "sprite %q defined twice", args[0])
+			}
+		}
+		p.cur = &Sprite{Name: args[0], Ticks: 1, file: p.file, line: p.line}
+		p.curLine = p.line
+		p.originX, p.originY = 0, 0
+		p.originLine = p.line
+		p.colors = map[rune]spritePaint{}
+		p.paints = map[string][]string{}
+		p.loop = nil
+	case "origin":
+		if len(args) != 2 {
+			return p.errorAt(p.line, "want \"origin <x> <y>\"")
+		}
+		x, errX := strconv.Atoi(args[0])
+		y, errY := strconv.Atoi(args[1])
+		if errX != nil || errY != nil || x < 0 || y < 0 {
+			return p.errorAt(p.line, "origin must be two whole numbers of 0 or more")
+		}
+		p.originX, p.originY = x, y
+		p.originLine = p.line
+	case "ticks":
+		if len(args) != 1 {
+			return p.errorAt(p.line, "want \"ticks <n>\"")
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return p.errorAt(p.line, "ticks must be a whole number of 1 or more")
+		}
+		p.cur.Ticks = n
+	case "color":
+		if len(args) != 2 || len([]rune(args[0])) != 1 {
+			return p.errorAt(p.line, "want \"color <key> <color>\" with a one character key")
+		}
+		key := []rune(args[0])[0]
+		if key == '.' {
+			return p.errorAt(p.line, "'.' always means fg and cannot be redefined")
+		}
+		paint, ok := parseSpritePaint(args[1])
+		if !ok {
+			return p.errorAt(p.line, "unknown color %q", args[1])
+		}
+		p.colors[key] = paint
+	case "loop":
+		if len(args) == 0 {
+			return p.errorAt(p.line, "want \"loop <frame> ...\"")
+		}
+		p.loop = args
+		p.loopLine = p.line
+	case "frame", "paint":
+		if len(args) != 1 {
+			return p.errorAt(p.line, "want \"%s <name>\"", directive)
+		}
+		p.block = &spriteBlock{kind: directive, name: args[0], line: p.line}
+	default:
+		return p.errorAt(p.line, "unknown directive %q", directive)
+	}
+	return nil
+}
+
+func (p *spriteParser) endBlock() error {
+	b := p.block
+	p.block = nil
+	// trailing blank rows are see-through anyway
+	for len(b.rows) > 0 && strings.TrimSpace(b.rows[len(b.rows)-1]) == "" {
+		b.rows = b.rows[:len(b.rows)-1]
+	}
+	if b.kind == "paint" {
+		if _, ok := p.paints[b.name]; ok {
+			return p.errorAt(b.line, "frame %q painted twice", b.name)
+		}
+		for i, row := range b.rows {
+			for _, r := range row {
+				if _, ok := p.colors[r]; !ok && r != ' ' && r != '.' {
+					return p.errorAt(b.line+1+i, "paint key %q has no \"color\" line", r)
+				}
+			}
+		}
+		p.paints[b.name] = b.rows
+		return nil
+	}
+	if len(b.rows) == 0 {
+		return p.errorAt(b.line, "frame %q is empty", b.name)
+	}
+	for _, f := range p.cur.Frames {
+		if f.Name == b.name {
+			return p.errorAt(b.line, "frame %q defined twice", b.name)
+		}
+	}
+	width := 0
+	for _, row := range b.rows {
+		width = max(width, len([]rune(row)))
+	}
+	f := &SpriteFrame{Name: b.name, Width: width, Height: len(b.rows)}
+	f.cells = make([]spriteCell, width*len(b.rows))
+	for y, row := range b.rows {
+		runes := []rune(row)
+		for x := 0; x < width; x++ {
+			r := ' '
+			if x < len(runes) {
+				r = runes[x]
+			}
+			f.cells[y*width+x] = spriteCell{r: r}
+		}
+	}
+	p.cur.Frames = append(p.cur.Frames, f)
+	return nil
+}
+
+// finish checks the sprite being read and adds it to the results.
+func (p *spriteParser) finish() error {
+	s := p.cur
+	if s == nil {
+		return nil
+	}
+	p.cur = nil
+	if len(s.Frames) == 0 {
+		return p.errorAt(p.curLine, "sprite %q has no frames", s.Name)
+	}
+	for name := range p.paints {
+		if s.frame(name) == nil {
+			return p.errorAt(p.curLine, "sprite %q paints frame %q, which it does not have", s.Name, name)
+		}
+	}
+	for _, f := range s.Frames {
+		if p.originX >= f.Width || p.originY >= f.Height {
+			return p.errorAt(p.originLine, "sprite %q: origin %d %d is outside frame %q (%dx%d)",
+				s.Name, p.originX, p.originY, f.Name, f.Width, f.Height)
+		}
+		f.OriginX, f.OriginY = p.originX, p.originY
+		for y, row := range p.paints[f.Name] {
+			for x, r := range []rune(row) {
+				if x >= f.Width || y >= f.Height {
+					if r != ' ' && r != '.' {
+						return p.errorAt(p.curLine, "sprite %q: paint for frame %q is bigger than the frame", s.Name, f.Name)
+					}
+					continue
+				}
+				if paint, ok := p.colors[r]; ok {
+					f.cells[y*f.Width+x].paint = paint
+				}
+			}
+		}
+		f.flipped = f.mirror()
+	}
+	if len(p.loop) == 0 {
+		for i := range s.Frames {
+			s.Loop = append(s.Loop, i)
+		}
+	}
+	for _, name := range p.loop {
+		i := s.frameIndex(name)
+		if i < 0 {
+			return p.errorAt(p.loopLine, "loop names frame %q, which sprite %q does not have", name, s.Name)
+		}
+		s.Loop = append(s.Loop, i)
+	}
+	p.sprites = append(p.sprites, s)
+	return nil
+}
+
+func (s *Sprite) frameIndex(name string) int {
+	for i, f := range s.Frames {
+		if f.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Sprite) frame(name string) *SpriteFrame {
+	if i := s.frameIndex(name); i >= 0 {
+		return s.Frames[i]
+	}
+	return nil
+}
+
+func parseSpritePaint(name string) (spritePaint, bool) {
+	switch name {
+	case "fg":
+		return spritePaint{role: paintFg}, true
+	case "hi":
+		return spritePaint{role: paintHi}, true
+	}
+	col := color.GetColor(strings.ToLower(name))
+	if col == color.Default {
+		return spritePaint{}, false
+	}
+	return spritePaint{role: paintFixed, color: col}, true
+}
+
+// SpriteSet holds the sprites critters are drawn with, by name, and the
+// cosmetic variants of each.
+type SpriteSet struct {
+	sprites  map[string]*Sprite
+	variants map[string][]*Sprite
+}
+
+//go:embed sprites/*.sprite
+var builtinSpriteFiles embed.FS
+
+// builtinSprites is parsed once from the embedded sprite files and never
+// changed afterwards.
+var builtinSprites = mustLoadBuiltinSprites()
+
+func mustLoadBuiltinSprites() map[string]*Sprite {
+	sprites := map[string]*Sprite{}
+	err := fs.WalkDir(builtinSpriteFiles, "sprites", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		f, err := builtinSpriteFiles.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		parsed, err := ParseSprites(path, f)
+		if err != nil {
+			return err
+		}
+		for _, s := range parsed {
+			sprites[s.Name] = s
+		}
+		return nil
+	})
+	if err != nil {
+		panic("kitty: built-in sprites: " + err.Error())
+	}
+	return sprites
+}
+
+// NewSpriteSet returns the built-in sprites.
+func NewSpriteSet() *SpriteSet {
+	set := &SpriteSet{sprites: map[string]*Sprite{}, variants: map[string][]*Sprite{}}
+	for name, s := range builtinSprites {
+		set.sprites[name] = s
+	}
+	return set
+}
+
+// Get returns the sprite called name. A nil set, or one without the
+// sprite, falls back to the built-in sprites.
+func (s *SpriteSet) Get(name string) *Sprite {
+	if s != nil {
+		if sp, ok := s.sprites[name]; ok {
+			return sp
+		}
+	}
+	return builtinSprites[name]
+}
+
+// Pick returns the sprite called name or, at random, one of its variants.
+// Critters pick once when they spawn and keep their look.
+func (s *SpriteSet) Pick(name string, w *World) *Sprite {
+	if s == nil || len(s.variants[name]) == 0 {
+		return s.Get(name)
+	}
+	i := w.Intn(len(s.variants[name]) + 1)
+	if i == 0 {
+		return s.Get(name)
+	}
+	return s.variants[name][i-1]
+}
+
+// LoadSprites reads the .sprite files in dir on top of the built-in
+// sprites. A sprite named after a built-in one, such as "butterfly",
+// replaces it; one named "<built-in>.<variant>", such as "butterfly.monarch",
+// adds a look critters pick from at random. Either must have every frame of
+// the built-in sprite. Problems are reported with the file and line.
+func LoadSprites(dir string) (*SpriteSet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	set := NewSpriteSet()
+	seen := map[string]*Sprite{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".sprite" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		sprites, err := ParseSprites(path, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, sp := range sprites {
+			if prev, ok := seen[sp.Name]; ok {
+				return nil, sp.errorf("sprite %q is already defined at %s:%d", sp.Name, prev.file, prev.line)
+			}
+			seen[sp.Name] = sp
+			if err := set.add(sp); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return set, nil
+}
+
+// add puts a loaded sprite in the set as an override or a variant.
+func (s *SpriteSet) add(sp *Sprite) error {
+	base, variant, isVariant := strings.Cut(sp.Name, ".")
+	builtin, ok := builtinSprites[base]
+	if !ok {
+		return sp.errorf("unknown sprite %q; sprites can replace %s or add a variant named like %q",
+			base, strings.Join(builtinSpriteNames(), ", "), "butterfly.monarch")
+	}
+	if isVariant && variant == "" {
+		return sp.errorf("sprite %q needs a variant name after the '.'", sp.Name)
+	}
+	for _, f := range builtin.Frames {
+		if sp.frame(f.Name) == nil {
+			return sp.errorf("sprite %q has no frame %q, which %s needs", sp.Name, f.Name, base)
+		}
+	}
+	if isVariant {
+		s.variants[base] = append(s.variants[base], sp)
+	} else {
+		s.sprites[base] = sp
+	}
+	return nil
+}
+
+func (s *Sprite) errorf(format string, args ...any) error {
+	return &SpriteError{File: s.file, Line: s.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func builtinSpriteNames() []string {
+	names := make([]string, 0, len(builtinSprites))
+	for name := range builtinSprites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Names lists the sprites in the set, variants included, sorted.
+func (s *SpriteSet) Names() []string {
+	names := make([]string, 0, len(s.sprites))
+	for name := range s.sprites {
+		names = append(names, name)
+	}
+	for _, variants := range s.variants {
+		for _, v := range variants {
+			names = append(names, v.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,290 @@
+package kitty
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSpritesErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  string
+		line int
+		want string
+	}{
+		{
+			name: "frame with no end",
+			src:  "sprite a\nframe one\nxx\n",
+			line: 2,
+			want: `frame "one" has no "end"`,
+		},
+		{
+			name: "paint with no end",
+			src:  "sprite a\nframe one\nxx\nend\npaint one\n..\n",
+			line: 5,
+			want: `paint "one" has no "end"`,
+		},
+		{
+			name: "unknown paint key",
+			src:  "sprite a\ncolor h hi\nframe one\nxx\nend\npaint one\nh\n.z\nend\n",
+			line: 8,
+			want: `paint key 'z' has no "color" line`,
+		},
+		{
+			name: "unknown color",
+			src:  "sprite a\ncolor h mauvish\n",
+			line: 2,
+			want: `unknown color "mauvish"`,
+		},
+		{
+			name: "negative origin",
+			src:  "sprite a\norigin -1 0\nframe one\nx\nend\n",
+			line: 2,
+			want: "origin must be two whole numbers",
+		},
+		{
+			name: "origin outside a frame",
+			src:  "sprite a\norigin 3 0\nframe one\nxx\nend\n",
+			line: 2,
+			want: `origin 3 0 is outside frame "one"`,
+		},
+		{
+			name: "directive before sprite",
+			src:  "# leading comment\nframe one\n",
+			line: 2,
+			want: `"frame" before the first "sprite"`,
+		},
+		{
+			name: "unknown directive",
+			src:  "sprite a\nscale 2\n",
+			line: 2,
+			want: `unknown directive "scale"`,
+		},
+		{
+			name: "sprite with no frames",
+			src:  "sprite a\n\nsprite b\nframe one\nx\nend\n",
+			line: 1,
+			want: `sprite "a" has no frames`,
+		},
+		{
+			name: "sprite defined twice",
+			src:  "sprite a\nframe one\nx\nend\nsprite a\n",
+			line: 5,
+			want: `sprite "a" defined twice`,
+		},
+		{
+			name: "loop names a missing frame",
+			src:  "sprite a\nframe one\nx\nend\nloop one two\n",
+			line: 5,
+			want: `loop names frame "two"`,
+		},
+		{
+			name: "bad ticks",
+			src:  "sprite a\nticks 0\n",
+			line: 2,
+			want: "ticks must be a whole number of 1 or more",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSprites("test.sprite", strings.NewReader(tc.src))
+			assertSpriteError(t, err, "test.sprite", tc.line, tc.want)
+		})
+	}
+}
+
+// assertSpriteError checks err is a *SpriteError at file and line whose
+// message holds want.
+func assertSpriteError(t *testing.T, err error, file string, line int, want string) {
+	t.Helper()
+	var serr *SpriteError
+	if !errors.As(err, &serr) {
+		t.Fatalf("err = %v, want a *SpriteError", err)
+	}
+	if serr.File != file || serr.Line != line || !strings.Contains(serr.Msg, want) {
+		t.Errorf("err = %v, want %s:%d: ...%s...", err, file, line, want)
+	}
+}
+
+func TestParseSprites(t *testing.T) {
+	src := `# a two frame sprite
+sprite blob
+origin 1 0
+ticks 3
+color h hi
+color r red
+loop big small big
+
+frame big
+(o)
+end
+paint big
+h.r
+end
+
+frame small
+ o
+end
+`
+	sprites, err := ParseSprites("blob.sprite", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sprites) != 1 {
+		t.Fatalf("parsed %d sprites, want 1", len(sprites))
+	}
+	s := sprites[0]
+	if s.Name != "blob" || s.Ticks != 3 || len(s.Frames) != 2 || len(s.Loop) != 3 {
+		t.Fatalf("parsed %+v", s)
+	}
+	big := s.Frame("big")
+	if big.Width != 3 || big.OriginX != 1 || big.cells[0].paint.role != paintHi || big.cells[2].paint.role != paintFixed {
+		t.Errorf("frame big = %+v", big)
+	}
+	if flip := big.Flip(); flip.cells[0].r != '(' || flip.cells[2].paint.role != paintHi || flip.Flip() != big {
+		t.Errorf("flipped big = %+v", flip)
+	}
+	if s.At(3) != s.Frame("small") {
+		t.Error("loop does not show small on its second step")
+	}
+}
+
+// writeSprites saves each file of sprites in dir.
+func writeSprites(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const monarchSprite = `# an orange butterfly
+sprite butterfly.monarch
+color h orange
+frame open
+\ /
+end
+frame closed
+/ \
+end
+`
+
+func TestLoadSprites(t *testing.T) {
+	dir := t.TempDir()
+	writeSprites(t, dir, map[string]string{
+		"monarch.sprite": monarchSprite,
+		"laser.sprite":   "sprite laser\nframe dot\n*\nend\n",
+		"notes.txt":      "not a sprite",
+	})
+	set, err := LoadSprites(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := set.Get("laser").Frame("dot").cells[0].r; got != '*' {
+		t.Errorf("laser override draws %q, want '*'", got)
+	}
+	if len(set.variants["butterfly"]) != 1 || set.variants["butterfly"][0].Name != "butterfly.monarch" {
+		t.Errorf("butterfly variants = %v", set.variants["butterfly"])
+	}
+	if set.Get("snake") != builtinSprites["snake"] {
+		t.Error("snake sprite is not the built-in one")
+	}
+}
+
+func TestLoadSpritesErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		files map[string]string
+		file  string
+		line  int
+		want  string
+	}{
+		{
+			name:  "override missing a built-in frame",
+			files: map[string]string{"b.sprite": "# open only\nsprite butterfly\nframe open\nx\nend\n"},
+			file:  "b.sprite",
+			line:  2,
+			want:  `has no frame "closed", which butterfly needs`,
+		},
+		{
+			name:  "unknown base name",
+			files: map[string]string{"c.sprite": "sprite comet.big\nframe head\n*\nend\n"},
+			file:  "c.sprite",
+			line:  1,
+			want:  `unknown sprite "comet"`,
+		},
+		{
+			name:  "empty variant name",
+			files: map[string]string{"d.sprite": "sprite laser.\nframe dot\n*\nend\n"},
+			file:  "d.sprite",
+			line:  1,
+			want:  "needs a variant name",
+		},
+		{
+			name: "duplicate across files",
+			files: map[string]string{
+				"a.sprite": monarchSprite,
+				"b.sprite": "\n\n" + monarchSprite,
+			},
+			file: "b.sprite",
+			line: 4,
+			want: `sprite "butterfly.monarch" is already defined at `,
+		},
+		{
+			name:  "parse error",
+			files: map[string]string{"e.sprite": "sprite laser\nframe dot\n*\n"},
+			file:  "e.sprite",
+			line:  2,
+			want:  `has no "end"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeSprites(t, dir, tc.files)
+			_, err := LoadSprites(dir)
+			assertSpriteError(t, err, filepath.Join(dir, tc.file), tc.line, tc.want)
+		})
+	}
+}
+
+func TestLoadSpritesMissingDir(t *testing.T) {
+	if _, err := LoadSprites(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("LoadSprites read a directory that does not exist")
+	}
+}
+
+// TestBuiltinSpritesHaveFrames checks the built-in sprites have every frame
+// the critters ask for; Frame falls back to the first one without a word.
+func TestBuiltinSpritesHaveFrames(t *testing.T) {
+	want := map[string][]string{
+		"bird":      {"up", "level", "down", "folded"},
+		"butterfly": {"open", "closed"},
+		"fish":      {"swim"},
+		"laser":     {"dot"},
+		"snake":     {"body", "head", "tail"},
+		"spider":    {"rest", "left", "right"},
+		"mouse":     {"body", "tail", "right", "left", "up", "down", "still", "hole"},
+		"fly":       {"far", "mid", "near", "rub", "rubbing", "stuck", "buzzing"},
+		"firefly":   {"dark", "lit", "bright"},
+		"wand":      {"still", "flutter"},
+		"yarn":      {"big0", "big1", "big2", "big3", "medium0", "medium1", "medium2", "medium3", "small0", "small1", "small2", "small3"},
+	}
+	for name, frames := range want {
+		s := builtinSprites[name]
+		if s == nil {
+			t.Errorf("no built-in %s sprite", name)
+			continue
+		}
+		for _, f := range frames {
+			if s.frame(f) == nil {
+				t.Errorf("built-in %s sprite has no %q frame", name, f)
+			}
+		}
+	}
+	if len(builtinSprites) != len(want) {
+		t.Errorf("%d built-in sprites, want the %d critters draw", len(builtinSprites), len(want))
+	}
+}
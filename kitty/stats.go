@@ -0,0 +1,177 @@
+package kitty
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// SessionStats records how much the cat actually played during one run.
+type SessionStats struct {
+	Start                    time.Time      `json:"start"`
+	End                      time.Time      `json:"end"`
+	DurationSeconds          float64        `json:"duration_seconds"`
+	Spawned                  map[string]int `json:"spawned"`
+	ButterfliesLasered       int            `json:"butterflies_lasered"`
+	ButterfliesWebbed        int            `json:"butterflies_webbed"`
+	ButterfliesEaten         int            `json:"butterflies_eaten"`
+	ButterfliesEatenBySnakes int            `json:"butterflies_eaten_by_snakes"`
+	ButterfliesEatenByBirds  int            `json:"butterflies_eaten_by_birds"`
+	MiceZapped               int            `json:"mice_zapped"`
+	MiceWebbed               int            `json:"mice_webbed"`
+	MiceEaten                int            `json:"mice_eaten"`
+	InsectsWebbed            int            `json:"insects_webbed"`
+	InsectsEaten             int            `json:"insects_eaten"`
+	BirdsTangled             int            `json:"birds_tangled"`
+	BirdsEaten               int            `json:"birds_eaten"`
+	SpidersDestroyed         int            `json:"spiders_destroyed"`
+	// Lasered counts other playthings the laser shot, by name.
+	Lasered map[string]int `json:"lasered,omitempty"`
+	Pounces int64          `json:"pounces"`
+}
+
+// KittySpawnCounter is implemented by playthings that count how many times
+// they have appeared on screen.
+type KittySpawnCounter interface {
+	Spawns() int
+}
+
+// sessionStats is the live counterpart of SessionStats. Pounces arrive from
+// the event loop goroutine, everything else from the play loop.
+type sessionStats struct {
+	stats   SessionStats
+	pounces atomic.Int64
+}
+
+func newSessionStats(start time.Time) *sessionStats {
+	return &sessionStats{stats: SessionStats{Start: start, Spawned: map[string]int{}, Lasered: map[string]int{}}}
+}
+
+// retire folds the spawn counts of playthings that are being removed.
+func (s *sessionStats) retire(objects []KittyPlayThing) {
+	for _, o := range objects {
+		if c, ok := o.(KittySpawnCounter); ok && c.Spawns() > 0 {
+			s.stats.Spawned[o.Name()] += c.Spawns()
+		}
+	}
+}
+
+func (s *sessionStats) snapshot(now time.Time, live []KittyPlayThing) SessionStats {
+	out := s.stats
+	out.Spawned = make(map[string]int, len(s.stats.Spawned))
+	for name, n := range s.stats.Spawned {
+		out.Spawned[name] = n
+	}
+	out.Lasered = make(map[string]int, len(s.stats.Lasered))
+	for name, n := range s.stats.Lasered {
+		out.Lasered[name] = n
+	}
+	for _, o := range live {
+		if c, ok := o.(KittySpawnCounter); ok && c.Spawns() > 0 {
+			out.Spawned[o.Name()] += c.Spawns()
+		}
+	}
+	out.End = now
+	out.DurationSeconds = now.Sub(out.Start).Seconds()
+	out.Pounces = s.pounces.Load()
+	return out
+}
+
+// lasered counts playthings the laser shot.
+func (s *sessionStats) lasered(name string) {
+	switch name {
+	case "butterfly":
+		s.stats.ButterfliesLasered++
+	case "mouse":
+		s.stats.MiceZapped++
+	default:
+		s.stats.Lasered[name]++
+	}
+}
+
+// webbed counts prey caught in a web.
+func (s *sessionStats) webbed(name string) {
+	switch name {
+	case "butterfly":
+		s.stats.ButterfliesWebbed++
+	case "mouse":
+		s.stats.MiceWebbed++
+	case "bird":
+		s.stats.BirdsTangled++
+	default:
+		s.stats.InsectsWebbed++
+	}
+}
+
+// eatenBySpider counts prey a spider ate.
+func (s *sessionStats) eatenBySpider(name string) {
+	switch name {
+	case "butterfly":
+		s.stats.ButterfliesEaten++
+	case "mouse":
+		s.stats.MiceEaten++
+	case "bird":
+		s.stats.BirdsEaten++
+	default:
+		s.stats.InsectsEaten++
+	}
+}
+
+// Summary renders the stats as a short human readable report.
+func (s SessionStats) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Play session: %s\n", time.Duration(s.DurationSeconds*float64(time.Second)).Round(time.Second))
+	names := make([]string, 0, len(s.Spawned))
+	for name := range s.Spawned {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-20s %d\n", name+" spawns", s.Spawned[name])
+	}
+	fmt.Fprintf(&b, "  %-20s %d\n", "butterflies lasered", s.ButterfliesLasered)
+	fmt.Fprintf(&b, "  %-20s %d\n", "butterflies webbed", s.ButterfliesWebbed)
+	fmt.Fprintf(&b, "  %-20s %d\n", "butterflies eaten", s.ButterfliesEaten)
+	fmt.Fprintf(&b, "  %-20s %d\n", "eaten by snakes", s.ButterfliesEatenBySnakes)
+	fmt.Fprintf(&b, "  %-20s %d\n", "eaten by birds", s.ButterfliesEatenByBirds)
+	fmt.Fprintf(&b, "  %-20s %d\n", "mice zapped", s.MiceZapped)
+	fmt.Fprintf(&b, "  %-20s %d\n", "mice webbed", s.MiceWebbed)
+	fmt.Fprintf(&b, "  %-20s %d\n", "mice eaten", s.MiceEaten)
+	fmt.Fprintf(&b, "  %-20s %d\n", "insects webbed", s.InsectsWebbed)
+	fmt.Fprintf(&b, "  %-20s %d\n", "insects eaten", s.InsectsEaten)
+	fmt.Fprintf(&b, "  %-20s %d\n", "birds tangled", s.BirdsTangled)
+	fmt.Fprintf(&b, "  %-20s %d\n", "birds eaten", s.BirdsEaten)
+	fmt.Fprintf(&b, "  %-20s %d\n", "spiders destroyed", s.SpidersDestroyed)
+	lasered := make([]string, 0, len(s.Lasered))
+	for name := range s.Lasered {
+		lasered = append(lasered, name)
+	}
+	sort.Strings(lasered)
+	for _, name := range lasered {
+		fmt.Fprintf(&b, "  %-20s %d\n", name+" lasered", s.Lasered[name])
+	}
+	fmt.Fprintf(&b, "  %-20s %d\n", "pounces", s.Pounces)
+	return b.String()
+}
+
+// AppendStats adds the stats as one JSON line to the history file at path,
+// creating it if needed.
+func AppendStats(path string, stats SessionStats) error {
+	line, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -1,23 +1,21 @@
 package kitty
 
 import (
+	"fmt"
 	"math"
-	"math/rand"
-	"time"
 
 	"github.com/gdamore/tcell/v3"
 	"github.com/gdamore/tcell/v3/color"
 )
 
 type SwayString struct {
+	Lifecycle
 	MinLen int
 	MaxLen int
 	Color  tcell.Color
 
-	respawnWait int
-	spawnDelay  int
-	initDelaySet bool
-	initialDelayMax int
+	speed       float64
+	intensity   float64
 	step        int
 	lifeSteps   int
 	length      int
@@ -33,70 +31,92 @@ type SwayString struct {
 	perpX   float64
 	perpY   float64
 	swingAmp float64
+	world *World
 }
 
-var swayRandSeeded bool
+func (s *SwayString) Name() string {
+	return "string"
+}
 
-func (s *SwayString) Update(screen tcell.Screen) {
-	if s.respawnWait > 0 {
-		s.respawnWait--
-		return
-	}
-	if s.lifeSteps == 0 {
-		if !s.initDelaySet {
-			ensureSwaySeeded()
-			maxDelay := s.initialDelayMax
-			if maxDelay <= 0 {
-				maxDelay = 40
-			}
-			s.spawnDelay = rand.Intn(maxDelay + 1)
-			s.initDelaySet = true
-		}
-		if s.spawnDelay > 0 {
-			s.spawnDelay--
-			return
-		}
-		s.initString(screen)
+func (s *SwayString) Layer() Layer {
+	return LayerWebs
+}
+
+func (s *SwayString) Update(w *World) {
+	s.world = w
+	if !s.Tick(s, w) {
 		return
 	}
 
+	act := activity(s.intensity)
 	s.step++
-	s.phase += 0.25
-	s.breezePhase += 0.12
+	s.phase += 0.25 * s.speed * act
+	s.breezePhase += 0.12 * s.speed * act
 	if s.breezeTicks > 0 {
 		s.breezeTicks--
-	} else if rand.Float64() < 0.015 {
-		s.breezeTicks = 40 + rand.Intn(80)
-		s.breezeDir = randRange(-1.0, 1.0)
+	} else if s.world.Float64() < 0.015*act {
+		s.breezeTicks = 40 + s.world.Intn(80)
+		s.breezeDir = s.world.Range(-1.0, 1.0)
 	}
 	if s.step >= s.lifeSteps {
-		s.lifeSteps = 0
-		s.respawnWait = 20 + rand.Intn(60)
+		s.Despawn(DespawnGone)
 	}
 }
 
-func (s *SwayString) Draw(screen tcell.Screen) {
-	if s.lifeSteps == 0 {
+func (s *SwayString) Spawn(w *World) {
+	s.world = w
+	s.initString(w.Width, w.Height)
+}
+
+func (s *SwayString) Despawn(reason DespawnReason) {
+	wait := 0
+	if reason != DespawnRemoved {
+		wait = calmWait(20+s.world.Intn(60), s.intensity)
+	}
+	s.lifeSteps = 0
+	s.Stop(wait)
+}
+
+func (s *SwayString) Bounds() Rect {
+	return boundsOf(s.points(), 1)
+}
+
+func (s *SwayString) Draw(c *Canvas) {
+	points := s.points()
+	if len(points) == 0 {
 		return
 	}
-	width, height := screen.Size()
 	fg := s.Color
 	if fg == tcell.ColorDefault || fg == 0 {
-		fg = randomStringColor()
+		fg = randomStringColor(s.world)
 		s.Color = fg
 	}
+	for _, p := range points {
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				c.Set(p.X+dx, p.Y+dy, tcell.RuneBlock, fg)
+			}
+		}
+	}
+}
 
+// points returns the center of every segment of the string as it is now.
+func (s *SwayString) points() []Point {
+	if !s.Active() || s.lifeSteps == 0 {
+		return nil
+	}
 	u := float64(s.step) / float64(max(1, s.lifeSteps-1))
 	lengthFactor := 1 - math.Abs(1-2*u)
 	curLen := int(math.Round(float64(s.length) * lengthFactor))
 	if curLen < 1 {
-		return
+		return nil
 	}
 
 	var breezeOffset float64
 	if s.breezeTicks > 0 {
 		breezeOffset = math.Sin(s.breezePhase) * (0.8 + 0.4*math.Sin(s.breezePhase*0.5)) * s.swingAmp * s.breezeDir
 	}
+	points := make([]Point, 0, curLen)
 	for i := 0; i < curLen; i++ {
 		flex := float64(i) / float64(max(1, curLen-1))
 		localSwing := math.Sin(s.phase+u*math.Pi*2+float64(i)*0.45) * s.swingAmp * (0.2 + 0.8*flex)
@@ -104,27 +124,19 @@ func (s *SwayString) Draw(screen tcell.Screen) {
 		wind := breezeOffset * (0.2 + 0.8*flex)
 		fx := float64(i)*s.dirX + (localSwing+bend+wind)*s.perpX
 		fy := float64(i)*s.dirY + (localSwing+bend+wind)*s.perpY
-		x := s.anchorX + int(math.Round(fx))
-		y := s.anchorY + int(math.Round(fy))
-		for dx := -1; dx <= 1; dx++ {
-			for dy := -1; dy <= 1; dy++ {
-				px := x + dx
-				py := y + dy
-				if px < 0 || py < 0 || px >= width || py >= height {
-					continue
-				}
-				screen.SetContent(px, py, tcell.RuneBlock, nil, tcell.StyleDefault.Foreground(fg))
-			}
-		}
+		points = append(points, Point{
+			X: s.anchorX + int(math.Round(fx)),
+			Y: s.anchorY + int(math.Round(fy)),
+		})
 	}
+	return points
 }
 
-func (s *SwayString) initString(screen tcell.Screen) {
-	width, height := screen.Size()
+func (s *SwayString) initString(width, height int) {
 	if width <= 0 || height <= 0 {
 		return
 	}
-	ensureSwaySeeded()
+	s.Start()
 
 	minLen := s.MinLen
 	maxLen := s.MaxLen
@@ -135,37 +147,41 @@ func (s *SwayString) initString(screen tcell.Screen) {
 		maxLen = minLen + 6
 	}
 
-	s.length = minLen + rand.Intn(maxLen-minLen+1)
-	s.lifeSteps = 40 + rand.Intn(80)
+	s.length = minLen + s.world.Intn(maxLen-minLen+1)
+	if s.speed <= 0 {
+		s.speed = 1.0
+	}
+	// slower strings linger proportionally longer
+	s.lifeSteps = int(float64(40+s.world.Intn(80)) / s.speed)
 	s.step = 0
-	s.phase = randRange(0, math.Pi*2)
-	s.breezePhase = randRange(0, math.Pi*2)
+	s.phase = s.world.Range(0, math.Pi*2)
+	s.breezePhase = s.world.Range(0, math.Pi*2)
 	s.breezeTicks = 0
-	s.breezeDir = randRange(-1.0, 1.0)
-	s.swingAmp = randRange(1.5, 6.5)
+	s.breezeDir = s.world.Range(-1.0, 1.0)
+	s.swingAmp = s.world.Range(1.5, 6.5)
 
 	if s.Color == tcell.ColorDefault || s.Color == 0 {
-		s.Color = randomStringColor()
+		s.Color = randomStringColor(s.world)
 	}
 
-	edge := rand.Intn(4)
+	edge := s.world.Intn(4)
 	if edge == 0 { // left
 		s.anchorX = 0
-		s.anchorY = rand.Intn(height)
+		s.anchorY = s.world.Intn(height)
 		s.dirX = 1
 		s.dirY = 0
 	} else if edge == 1 { // right
 		s.anchorX = width - 1
-		s.anchorY = rand.Intn(height)
+		s.anchorY = s.world.Intn(height)
 		s.dirX = -1
 		s.dirY = 0
 	} else if edge == 2 { // top
-		s.anchorX = rand.Intn(width)
+		s.anchorX = s.world.Intn(width)
 		s.anchorY = 0
 		s.dirX = 0
 		s.dirY = 1
 	} else { // bottom
-		s.anchorX = rand.Intn(width)
+		s.anchorX = s.world.Intn(width)
 		s.anchorY = height - 1
 		s.dirX = 0
 		s.dirY = -1
@@ -175,6 +191,27 @@ func (s *SwayString) initString(screen tcell.Screen) {
 	s.perpY = s.dirX
 }
 
+// Leave lets the string retract back to its anchor without growing again.
+func (s *SwayString) Leave() {
+	s.Lifecycle.Leave()
+	if s.lifeSteps > 0 && s.step*2 < s.lifeSteps {
+		// still growing, jump to the mirror point of the retract
+		s.step = s.lifeSteps - 1 - s.step
+	}
+}
+
+func (s *SwayString) SetIntensity(intensity float64) {
+	s.intensity = intensity
+}
+
+func (s *SwayString) DebugInfo() (int, int, string, bool) {
+	if s.lifeSteps == 0 {
+		return 0, 0, "", false
+	}
+	info := fmt.Sprintf("string %d/%d swing=%.1f breeze=%d", s.step, s.lifeSteps, s.swingAmp, s.breezeTicks)
+	return s.anchorX, s.anchorY, info, true
+}
+
 func NewSwayString(cfg SwayStringConfig) *SwayString {
 	if cfg.MinLen <= 0 {
 		cfg.MinLen = 18
@@ -185,15 +222,20 @@ func NewSwayString(cfg SwayStringConfig) *SwayString {
 	if cfg.InitialDelayMax <= 0 {
 		cfg.InitialDelayMax = 40
 	}
+	if cfg.Speed <= 0 {
+		cfg.Speed = 1.0
+	}
 	return &SwayString{
-		MinLen:          cfg.MinLen,
-		MaxLen:          cfg.MaxLen,
-		Color:           cfg.Color,
-		initialDelayMax: cfg.InitialDelayMax,
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		MinLen:    cfg.MinLen,
+		MaxLen:    cfg.MaxLen,
+		Color:     cfg.Color,
+		speed:     cfg.Speed,
+		intensity: 1.0,
 	}
 }
 
-func randomStringColor() tcell.Color {
+func randomStringColor(w *World) tcell.Color {
 	colors := []tcell.Color{
 		color.Red,
 		color.Orange,
@@ -209,12 +251,5 @@ func randomStringColor() tcell.Color {
 		color.Lime,
 		color.White,
 	}
-	return colors[rand.Intn(len(colors))]
+	return colors[w.Intn(len(colors))]
 }
-
-func ensureSwaySeeded() {
-	if !swayRandSeeded {
-		rand.Seed(time.Now().UnixNano())
-		swayRandSeeded = true
-	}
-}
\ No newline at end of file
@@ -0,0 +1,194 @@
+package kitty
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// World is what playthings can see of each other and everything they share
+// within one Kitty: the screen size and the random source. Kitty refreshes
+// it once per tick, before any plaything updates, so every plaything sees
+// the same scene. Each Kitty has its own World, so several can run in one
+// process without sharing state.
+type World struct {
+	Width  int
+	Height int
+
+	// WebPoints are the intact web threads of every spider.
+	WebPoints []Point
+	// Butterflies are the butterflies flying free, not stuck in a web.
+	Butterflies []Point
+	// Lasers are the laser dots currently on screen.
+	Lasers []Point
+	// Beams are the laser dots that are firing right now.
+	Beams []Point
+	// Snakes are all snakes, including ones waiting to spawn.
+	Snakes []*Snake
+	// Pounces are where the cat clicked since the last tick.
+	Pounces []Point
+	// Drag is where the mouse button is held down while Dragging.
+	Drag     Point
+	Dragging bool
+	// DragStart is where the button went down. DragStarted is set for the
+	// one tick after it did, so only a drag that starts on a plaything
+	// picks it up.
+	DragStart   Point
+	DragStarted bool
+	// Grabbed is the plaything the current drag picked up, if any, so two
+	// never follow the same drag. It is cleared when the button is let go.
+	Grabbed KittyPlayThing
+	// Effects takes the particles playthings emit, such as bursts and
+	// trails.
+	Effects *Effects
+	// Sprites are the pictures critters are drawn with.
+	Sprites *SpriteSet
+
+	rng *rand.Rand
+}
+
+// NewWorld returns an empty world whose random source starts from seed.
+func NewWorld(seed int64) *World {
+	return &World{rng: rand.New(rand.NewSource(seed)), Sprites: NewSpriteSet()}
+}
+
+// Intn returns a random int in [0, n). A nil World falls back to the
+// math/rand top level functions, which are safe for concurrent use.
+func (w *World) Intn(n int) int {
+	if w == nil || w.rng == nil {
+		return rand.Intn(n)
+	}
+	return w.rng.Intn(n)
+}
+
+// Float64 returns a random float64 in [0, 1).
+func (w *World) Float64() float64 {
+	if w == nil || w.rng == nil {
+		return rand.Float64()
+	}
+	return w.rng.Float64()
+}
+
+// Range returns a random float64 in [minV, maxV).
+func (w *World) Range(minV, maxV float64) float64 {
+	return minV + w.Float64()*(maxV-minV)
+}
+
+// Sighting is something a plaything can see near it: what kind of thing it
+// is ("laser", "butterfly", "snake" or "pounce"), where it is and how far
+// away, in rows.
+type Sighting struct {
+	Kind     string
+	Point    Point
+	Distance float64
+}
+
+// Nearby lists the lasers, butterflies, snake heads and pounces within
+// radius rows of (x, y), nearest first. Columns count half as much as rows,
+// as everywhere else.
+func (w *World) Nearby(x, y, radius float64) []Sighting {
+	var found []Sighting
+	add := func(kind string, p Point) {
+		if d := math.Hypot((float64(p.X)-x)/cellAspect, float64(p.Y)-y); d <= radius {
+			found = append(found, Sighting{Kind: kind, Point: p, Distance: d})
+		}
+	}
+	for _, p := range w.Lasers {
+		add("laser", p)
+	}
+	for _, p := range w.Butterflies {
+		add("butterfly", p)
+	}
+	for _, s := range w.Snakes {
+		if p, ok := s.Head(); ok {
+			add("snake", p)
+		}
+	}
+	for _, p := range w.Pounces {
+		add("pounce", p)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Distance < found[j].Distance })
+	return found
+}
+
+// pointerState hands mouse input from the event loop to the play loop:
+// clicks queue up until the next tick, drags only keep the latest position.
+type pointerState struct {
+	mu        sync.Mutex
+	pounces   []Point
+	drag      Point
+	dragging  bool
+	dragStart Point
+	started   bool
+}
+
+func (q *pointerState) press(p Point) {
+	q.mu.Lock()
+	q.pounces = append(q.pounces, p)
+	q.mu.Unlock()
+}
+
+// move records where the button is held down, or that it was released.
+func (q *pointerState) move(p Point, down bool) {
+	q.mu.Lock()
+	if down && !q.dragging {
+		q.dragStart = p
+		q.started = true
+	}
+	q.drag = p
+	q.dragging = down
+	q.mu.Unlock()
+}
+
+// apply copies the input into w and empties the click queue.
+func (q *pointerState) apply(w *World) {
+	q.mu.Lock()
+	w.Pounces = append(w.Pounces[:0], q.pounces...)
+	q.pounces = q.pounces[:0]
+	w.Drag = q.drag
+	w.Dragging = q.dragging
+	w.DragStart = q.dragStart
+	w.DragStarted = q.started && q.dragging
+	q.started = false
+	if !w.Dragging {
+		w.Grabbed = nil
+	}
+	q.mu.Unlock()
+}
+
+// refreshWorld rebuilds the world from the current playthings.
+func (k *Kitty) refreshWorld() {
+	width, height := k.s.Size()
+	w := k.world
+	w.Width = width
+	w.Height = height
+	w.WebPoints = w.WebPoints[:0]
+	w.Butterflies = w.Butterflies[:0]
+	w.Lasers = w.Lasers[:0]
+	w.Beams = w.Beams[:0]
+	w.Snakes = w.Snakes[:0]
+	k.pointer.apply(w)
+	for _, o := range k.objects {
+		switch o := o.(type) {
+		case *Spider:
+			w.WebPoints = append(w.WebPoints, o.GetWebPoints()...)
+		case *Butterfly:
+			if o.IsStuckInWeb() {
+				continue
+			}
+			if x, y, ok := o.HitPoint(width, height); ok {
+				w.Butterflies = append(w.Butterflies, Point{X: x, Y: y})
+			}
+		case *LaserPointer:
+			if p, ok := o.Position(width, height); ok {
+				w.Lasers = append(w.Lasers, p)
+				if o.Firing() {
+					w.Beams = append(w.Beams, p)
+				}
+			}
+		case *Snake:
+			w.Snakes = append(w.Snakes, o)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package kitty
+
+import (
+	"sync"
+	"testing"
+)
+
+// newSceneKitty builds a Kitty with a full play scene spawned. It calls
+// t.Fatal, so it must run on the test goroutine.
+func newSceneKitty(t *testing.T, seed int64) *Kitty {
+	t.Helper()
+	cfg := DefaultKittyConfig()
+	cfg.Seed = seed
+	cfg.LaserHitsSpiders = true
+	k := newTestKitty(t, cfg)
+	k.spawnForPhase(SessionPlay)
+	return k
+}
+
+// playScene runs k for ticks frames, then reports where everything ended
+// up. It does not touch the test, so it is safe to run on any goroutine.
+func playScene(k *Kitty, ticks int) []Rect {
+	for i := 0; i < ticks; i++ {
+		k.step()
+		k.draw()
+	}
+	bounds := make([]Rect, len(k.objects))
+	for i, o := range k.objects {
+		bounds[i] = o.Bounds()
+	}
+	return bounds
+}
+
+func sameBounds(a, b []Rect) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestKittysPlayConcurrently(t *testing.T) {
+	t.Parallel()
+	const ticks = 300
+	alone := playScene(newSceneKitty(t, 1), ticks)
+
+	// the same seed plays out the same way while other Kittys run beside
+	// it, so nothing leaks between them
+	kittys := make([]*Kitty, 4)
+	for i := range kittys {
+		kittys[i] = newSceneKitty(t, int64(i%2+1))
+	}
+	var wg sync.WaitGroup
+	results := make([][]Rect, len(kittys))
+	for i, k := range kittys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = playScene(k, ticks)
+		}()
+	}
+	wg.Wait()
+	for i, got := range results {
+		if i%2 == 0 && !sameBounds(got, alone) {
+			t.Errorf("Kitty %d with seed 1 diverged from the one that played alone", i)
+		}
+	}
+	if sameBounds(results[0], results[1]) {
+		t.Error("Kittys with seeds 1 and 2 played out the same")
+	}
+}
+
+func TestWorldsAreIndependent(t *testing.T) {
+	t.Parallel()
+	draw := func(w *World) []int {
+		out := make([]int, 100)
+		for i := range out {
+			out[i] = w.Intn(1000)
+		}
+		return out
+	}
+	want := draw(NewWorld(7))
+
+	var wg sync.WaitGroup
+	got := make([][]int, 8)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = draw(NewWorld(7))
+		}()
+	}
+	wg.Wait()
+	for i := range got {
+		for j := range want {
+			if got[i][j] != want[j] {
+				t.Fatalf("World %d drew %d at %d, want %d", i, got[i][j], j, want[j])
+			}
+		}
+	}
+}
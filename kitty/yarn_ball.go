@@ -0,0 +1,332 @@
+package kitty
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+// YarnBall rolls along the bottom of the screen, bouncing off the edges and
+// slowing with friction. It unspools a wiggling trail of yarn as it rolls and
+// shrinks as it goes; once it runs out, the trail is slowly reeled back in
+// before the next ball rolls on. The laser dot or a pounce bats it away.
+type YarnBall struct {
+	Lifecycle
+	Color tcell.Color
+
+	x          float64
+	y          float64
+	vx         float64
+	vy         float64
+	friction   float64
+	length     int
+	trail      []float64
+	rolled     float64
+	spin       float64
+	phase      float64
+	swingAmp   float64
+	batTicks   int
+	retracting bool
+	yarnColor  tcell.Color
+	intensity  float64
+	log        *slog.Logger
+	sprite     *Sprite
+	world      *World
+}
+
+// yarnMaxRadius and yarnMinRadius are the ball's radius in rows when fully
+// wound and when nearly used up.
+const (
+	yarnMaxRadius = 1.5
+	yarnMinRadius = 0.5
+)
+
+// yarnSpinFrames is how many frames each size of the yarn sprite turns
+// through as the ball rolls.
+const yarnSpinFrames = 4
+
+func (y *YarnBall) Name() string {
+	return "yarn"
+}
+
+func (y *YarnBall) Layer() Layer {
+	return LayerCritters
+}
+
+func (y *YarnBall) Update(w *World) {
+	y.world = w
+	width, height := w.Width, w.Height
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if !y.Tick(y, w) {
+		return
+	}
+	act := activity(y.intensity)
+	y.phase += 0.25 * act
+	if y.batTicks > 0 {
+		y.batTicks--
+	}
+
+	if !y.retracting && (y.Leaving() || len(y.trail) >= y.length) {
+		y.log.Debug("yarn retracting", "x", math.Round(y.x), "trail", len(y.trail))
+		y.retracting = true
+	}
+	if y.retracting {
+		y.retract()
+	} else {
+		y.bat(w)
+		if y.onGround(height) && math.Abs(y.vx) < 0.1 && y.world.Float64() < 0.01*act {
+			// nothing is playing with it; let it roll off on its own
+			y.vx = y.world.Range(1.0, 2.0) * y.awayFromEdge(width)
+		}
+	}
+	y.move(width, height)
+
+	if y.retracting && len(y.trail) == 0 {
+		y.Despawn(DespawnGone)
+	}
+}
+
+// bat knocks the ball away from the laser dot or a pounce that touches it.
+func (y *YarnBall) bat(w *World) {
+	if y.batTicks > 0 {
+		return
+	}
+	r := y.radius()
+	for _, p := range append(append([]Point(nil), w.Lasers...), w.Pounces...) {
+		dx := y.x - float64(p.X)
+		if math.Abs(dx) > r*cellAspect+1 || math.Abs(y.y-float64(p.Y)) > r+1 {
+			continue
+		}
+		dir := math.Copysign(1, dx)
+		if dx == 0 {
+			dir = y.awayFromEdge(w.Width)
+		}
+		y.vx = dir * y.world.Range(2.0, 4.0)
+		y.vy = -y.world.Range(1.5, 3.0)
+		y.batTicks = 10
+		y.world.Effects.Emit(fxSlitherDust, y.x, y.y+r)
+		y.log.Debug("yarn batted", "x", math.Round(y.x), "y", math.Round(y.y), "vx", y.vx)
+		return
+	}
+}
+
+// move applies gravity and friction, bounces off the floor and the side
+// edges and unspools yarn for every column rolled along the floor.
+func (y *YarnBall) move(width, height int) {
+	r := y.radius()
+	rest := y.restY(height)
+	rx := r * cellAspect
+
+	var landed bool
+	y.y, y.vy, landed = bounce(y.y, y.vy, ballGravity, rest, 0.5)
+	if landed && math.Abs(y.vy) < 0.6 {
+		y.vy = 0
+	}
+	if y.onGround(height) {
+		y.vx *= y.friction
+		if y.retracting {
+			y.vx *= 0.8
+		}
+	}
+
+	prevX := y.x
+	y.x += y.vx
+	if y.x < rx {
+		y.x = rx
+		y.vx = -y.vx * 0.8
+	}
+	if right := float64(width-1) - rx; y.x > right {
+		y.x = math.Max(right, rx)
+		y.vx = -y.vx * 0.8
+	}
+	y.spin += y.x - prevX
+
+	if y.retracting || !y.onGround(height) {
+		return
+	}
+	y.rolled += math.Abs(y.x - prevX)
+	for y.rolled >= 1 && len(y.trail) < y.length {
+		y.rolled--
+		y.trail = append(y.trail, y.x)
+	}
+}
+
+// retract reels the trail back in from its far end, a little at a time.
+func (y *YarnBall) retract() {
+	n := 1
+	if !y.Leaving() && y.world.Intn(3) != 0 {
+		n = 0
+	}
+	y.trail = y.trail[min(n, len(y.trail)):]
+}
+
+// radius shrinks from yarnMaxRadius to yarnMinRadius as the yarn unspools.
+func (y *YarnBall) radius() float64 {
+	used := float64(len(y.trail)) / float64(max(1, y.length))
+	return yarnMaxRadius - (yarnMaxRadius-yarnMinRadius)*used
+}
+
+func (y *YarnBall) restY(height int) float64 {
+	return float64(height-1) - math.Floor(y.radius())
+}
+
+func (y *YarnBall) onGround(height int) bool {
+	return y.y >= y.restY(height) && y.vy == 0
+}
+
+// awayFromEdge is the direction towards the middle of the screen.
+func (y *YarnBall) awayFromEdge(width int) float64 {
+	if y.x < float64(width)/2 {
+		return 1
+	}
+	return -1
+}
+
+func (y *YarnBall) Spawn(w *World) {
+	y.world = w
+	y.Start()
+	y.sprite = w.Sprites.Pick("yarn", w)
+	y.trail = y.trail[:0]
+	y.retracting = false
+	y.rolled = 0
+	y.batTicks = 0
+	rx := yarnMaxRadius * cellAspect
+	y.x = rx
+	y.vx = y.world.Range(1.5, 2.5)
+	if y.world.Intn(2) == 0 {
+		y.x = math.Max(float64(w.Width-1)-rx, rx)
+		y.vx = -y.vx
+	}
+	y.y = y.restY(w.Height)
+	y.vy = 0
+	y.phase = y.world.Range(0, math.Pi*2)
+	y.swingAmp = y.world.Range(0.8, 1.6)
+	y.yarnColor = y.Color
+	if y.yarnColor == tcell.ColorDefault || y.yarnColor == 0 {
+		y.yarnColor = randomStringColor(y.world)
+	}
+}
+
+func (y *YarnBall) Despawn(reason DespawnReason) {
+	wait := 0
+	if reason != DespawnRemoved {
+		wait = calmWait(60+y.world.Intn(140), y.intensity)
+	}
+	y.trail = y.trail[:0]
+	y.retracting = false
+	y.Stop(wait)
+}
+
+// points is the yarn trail as it lies on the floor, wiggling like a
+// SwayString; the end nearest the ball moves the most.
+func (y *YarnBall) points() []Point {
+	if len(y.trail) == 0 || y.world == nil {
+		return nil
+	}
+	floor := float64(y.world.Height - 1)
+	points := make([]Point, 0, len(y.trail))
+	for i, tx := range y.trail {
+		flex := float64(i) / float64(max(1, len(y.trail)-1))
+		swing := math.Sin(y.phase+float64(i)*0.45) * y.swingAmp * (0.2 + 0.8*flex)
+		p := Point{X: int(math.Round(tx)), Y: int(math.Round(floor - math.Abs(swing)))}
+		if len(points) > 0 && points[len(points)-1] == p {
+			continue
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// HitPoint is the middle of the ball.
+func (y *YarnBall) HitPoint(width, height int) (int, int, bool) {
+	if !y.Active() {
+		return 0, 0, false
+	}
+	x, yy := int(math.Round(y.x)), int(math.Round(y.y))
+	if x < 0 || yy < 0 || x >= width || yy >= height {
+		return 0, 0, false
+	}
+	return x, yy, true
+}
+
+// Bounds covers the ball and the trail it left behind.
+func (y *YarnBall) Bounds() Rect {
+	if !y.Active() {
+		return Rect{}
+	}
+	r := y.radius()
+	ball := rectAround(int(math.Round(y.x)), int(math.Round(y.y)), int(math.Ceil(r*cellAspect)), int(math.Ceil(r)))
+	if points := y.points(); len(points) > 0 {
+		return ball.Union(boundsOf(points, 0))
+	}
+	return ball
+}
+
+func (y *YarnBall) Draw(c *Canvas) {
+	if !y.Active() {
+		return
+	}
+	points := y.points()
+	for i := 1; i < len(points); i++ {
+		drawChainLink(c, points[i-1], points[i], y.yarnColor)
+	}
+	cx, cy := int(math.Round(y.x)), int(math.Round(y.y))
+	if len(points) > 0 && !y.retracting {
+		// the strand still runs from the floor into the ball
+		drawChainLink(c, points[len(points)-1], Point{X: cx, Y: cy}, y.yarnColor)
+	}
+
+	size := "small"
+	switch r := y.radius(); {
+	case r > 1.25:
+		size = "big"
+	case r > 0.75:
+		size = "medium"
+	}
+	turn := ((int(math.Floor(y.spin)) % yarnSpinFrames) + yarnSpinFrames) % yarnSpinFrames
+	y.sprite.Frame(fmt.Sprintf("%s%d", size, turn)).Draw(c, cx, cy, y.yarnColor, y.yarnColor)
+}
+
+func (y *YarnBall) SetIntensity(intensity float64) {
+	y.intensity = intensity
+}
+
+func (y *YarnBall) SetLogger(log *slog.Logger) {
+	y.log = log
+}
+
+func (y *YarnBall) DebugInfo() (int, int, string, bool) {
+	x, yy, ok := y.HitPoint(math.MaxInt32, math.MaxInt32)
+	if !ok {
+		return 0, 0, "", false
+	}
+	state := "roll"
+	if y.retracting {
+		state = "retract"
+	}
+	return x, yy, fmt.Sprintf("yarn %s vx=%.1f trail=%d/%d", state, y.vx, len(y.trail), y.length), true
+}
+
+func NewYarnBall(cfg YarnBallConfig) *YarnBall {
+	if cfg.InitialDelayMax <= 0 {
+		cfg.InitialDelayMax = 70
+	}
+	if cfg.Length <= 0 {
+		cfg.Length = 60
+	}
+	if cfg.Friction <= 0 || cfg.Friction > 1 {
+		cfg.Friction = 0.97
+	}
+	return &YarnBall{
+		Lifecycle: Lifecycle{InitialDelayMax: cfg.InitialDelayMax},
+		Color:     cfg.Color,
+		length:    cfg.Length,
+		friction:  cfg.Friction,
+		intensity: 1.0,
+		log:       discardLogger,
+	}
+}